@@ -0,0 +1,50 @@
+package mlfq
+
+// agingList 是单个 level 内按 enqueuedAt 升序排列的侵入式双向链表。
+//
+// Tick 从链表头（等待最久的任务）开始扫描，遇到第一个不满足老化条件的任务即可停止；
+// 插入/删除均为 O(1)（乱序插入时向前回溯，常见情况下直接追加到尾部）。
+type agingList[T any] struct {
+	head, tail *taskState[T]
+	n          int
+}
+
+func (l *agingList[T]) Len() int { return l.n }
+
+// insert 按 enqueuedAt 有序插入；enqueuedAt 相同时保持插入顺序。
+func (l *agingList[T]) insert(st *taskState[T]) {
+	at := l.tail
+	for at != nil && at.enqueuedAt.After(st.enqueuedAt) {
+		at = at.agePrev
+	}
+	// 插入到 at 之后（at 为 nil 表示插入到头部）
+	st.agePrev = at
+	if at == nil {
+		st.ageNext = l.head
+		l.head = st
+	} else {
+		st.ageNext = at.ageNext
+		at.ageNext = st
+	}
+	if st.ageNext == nil {
+		l.tail = st
+	} else {
+		st.ageNext.agePrev = st
+	}
+	l.n++
+}
+
+func (l *agingList[T]) remove(st *taskState[T]) {
+	if st.agePrev == nil {
+		l.head = st.ageNext
+	} else {
+		st.agePrev.ageNext = st.ageNext
+	}
+	if st.ageNext == nil {
+		l.tail = st.agePrev
+	} else {
+		st.ageNext.agePrev = st.agePrev
+	}
+	st.agePrev, st.ageNext = nil, nil
+	l.n--
+}
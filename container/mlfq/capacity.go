@@ -0,0 +1,128 @@
+package mlfq
+
+import "context"
+
+// Admission 决定队列已满时 Submit 的行为。
+type Admission int8
+
+const (
+	// AdmitReject 直接拒绝，Submit 返回 ErrFull（默认）。
+	AdmitReject Admission = iota
+	// AdmitEvict 淘汰一个最低优先级 level 中等待最久的任务为新任务腾出空间（见 WithEvictCallback）；
+	// 若被淘汰者的优先级高于新任务，或没有排队中的任务可淘汰（只有等待前置任务或延迟中的任务），则改为拒绝。
+	AdmitEvict
+	// AdmitBlock 阻塞 Submit，直到有空位、ctx 结束或调度器关闭。
+	AdmitBlock
+)
+
+// WithCapacity 限制调度器中等待执行的任务总数（排队、等待前置任务与延迟中，不包含已发放 Lease 的任务）；
+// n<=0 表示不限制。
+//
+// 容量只约束 Submit：FeedBack 重新入队等内部流转不受限制，以免丢失执行中的任务。
+func WithCapacity(n int) Option {
+	return func(c *config) {
+		c.capacity = n
+	}
+}
+
+// WithLevelCapacity 限制单个 level 的排队任务数；n<=0 表示不限制。
+//
+// 只统计已在 level 中排队的任务：等待前置任务或延迟中的任务在 Submit 时按目标 level 检查，到期入队时不再检查。
+func WithLevelCapacity(n int) Option {
+	return func(c *config) {
+		c.levelCapacity = n
+	}
+}
+
+// WithAdmission 设置队列满时的准入策略，默认 AdmitReject。
+func WithAdmission(a Admission) Option {
+	return func(c *config) {
+		c.admission = a
+	}
+}
+
+// WithEvictCallback 设置 AdmitEvict 淘汰任务时的回调；T 需与调度器的任务类型一致，否则 New 返回 ErrEvictCallback。
+//
+// 回调在调度器锁内同步执行，不得在回调中调用调度器方法。
+func WithEvictCallback[T any](fn func(token Token, task T)) Option {
+	return func(c *config) {
+		c.onEvict = fn
+	}
+}
+
+// hasRoomLocked 判断当前是否还能向 level 提交新任务。
+func (s *scheduler[T]) hasRoomLocked(level int) bool {
+	if s.cfg.capacity > 0 && s.mq.TotalLen()+s.held+s.delayed.Len() >= s.cfg.capacity {
+		return false
+	}
+	if s.cfg.levelCapacity > 0 && s.mq.Len(level) >= s.cfg.levelCapacity {
+		return false
+	}
+	return true
+}
+
+// admitLocked 按准入策略为 level 腾出空间。
+//
+// waited=true 表示期间释放过锁等待（AdmitBlock），调用方需要重新检查调度器状态后以 retry=true 再次调用。
+func (s *scheduler[T]) admitLocked(ctx context.Context, level int, retry bool) (waited bool, err error) {
+	for !s.hasRoomLocked(level) {
+		switch s.cfg.admission {
+		case AdmitEvict:
+			if !s.evictLocked(level) {
+				s.rejected++
+				return false, ErrFull
+			}
+		case AdmitBlock:
+			w := s.space.add(level, retry)
+			s.mu.Unlock()
+			select {
+			case <-w.ch:
+				s.mu.Lock()
+				return true, nil
+			case <-ctx.Done():
+				s.mu.Lock()
+				if !s.space.remove(w) {
+					// 已被唤醒但放弃提交：把空位转交给下一个等待者。
+					s.wakeSpaceLocked()
+				}
+				return false, ctx.Err()
+			}
+		default:
+			s.rejected++
+			return false, ErrFull
+		}
+	}
+	return false, nil
+}
+
+// evictLocked 淘汰一个任务：level 已满时淘汰该 level 最老的任务，否则淘汰最低优先级 level 最老的任务。
+func (s *scheduler[T]) evictLocked(level int) bool {
+	victimLevel := level
+	if s.cfg.levelCapacity <= 0 || s.mq.Len(level) < s.cfg.levelCapacity {
+		lowest, ok := s.mq.MaxNonEmpty()
+		if !ok || lowest < level {
+			// 现有任务的优先级都高于新任务：不为低优先级任务淘汰高优先级任务。
+			return false
+		}
+		victimLevel = lowest
+	}
+	st := s.aging[victimLevel].head
+	if st == nil || !s.unqueueLocked(st) {
+		return false
+	}
+	s.dropLocked(st, ErrEvicted)
+	s.evicted++
+	s.logRemove(journalEvict, st)
+	s.observe(EventEvicted, st, st.level, s.cfg.now(), false)
+	if s.onEvict != nil {
+		s.onEvict(st.token, st.task)
+	}
+	return true
+}
+
+// wakeSpaceLocked 在腾出一个排队位置后唤醒一个能用上它的 AdmitBlock 等待者。
+func (s *scheduler[T]) wakeSpaceLocked() {
+	if s.space.Len() > 0 {
+		s.space.wakeFirst(s.hasRoomLocked)
+	}
+}
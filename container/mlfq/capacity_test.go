@@ -0,0 +1,184 @@
+package mlfq
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestScheduler_CapacityReject(t *testing.T) {
+	ctx := context.Background()
+	s, _ := NewDefault[int](4, WithCapacity(2))
+	defer s.Close()
+
+	_, _ = s.Submit(ctx, 1)
+	_, _ = s.Submit(ctx, 2)
+	if _, err := s.Submit(ctx, 3); err != ErrFull {
+		t.Fatalf("expected ErrFull got %v", err)
+	}
+	// Lease 中的任务不占容量
+	_, _ = s.Next(ctx)
+	if _, err := s.Submit(ctx, 3); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if st := s.Stats(ctx); st.Rejected != 1 || st.TotalLen != 2 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+}
+
+func TestScheduler_CapacityCountsDelayedAndHeld(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Unix(0, 0)
+	s, _ := NewDefault[int](4, WithCapacity(2), WithAdmission(AdmitBlock), WithClock(func() time.Time { return clock }))
+	defer s.Close()
+
+	// 延迟中与等待前置任务的任务同样占用容量。
+	pre, _ := s.Submit(ctx, 1, WithDelay(time.Second))
+	_, _ = s.Submit(ctx, 2, WithAfter(pre))
+	tctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.Submit(tctx, 3, WithDelay(time.Second)); err != context.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded got %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Submit(ctx, 4)
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	// 取消延迟中的任务（级联取消其后继）释放空位。
+	_ = s.Cancel(ctx, pre)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("submit: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("blocked submit not woken")
+	}
+	if st := s.Stats(ctx); st.TotalLen != 1 || st.Delayed != 0 || st.Held != 0 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+}
+
+func TestScheduler_LevelCapacityEvict(t *testing.T) {
+	ctx := context.Background()
+	var evicted []int
+	s, _ := NewDefault[int](3,
+		WithLevelCapacity(2),
+		WithAdmission(AdmitEvict),
+		WithEvictCallback(func(_ Token, task int) { evicted = append(evicted, task) }),
+	)
+	defer s.Close()
+
+	low := WithAttributes(levelAttrs(2))
+	_, _ = s.Submit(ctx, 1, low)
+	_, _ = s.Submit(ctx, 2, low)
+	_, _ = s.Submit(ctx, 3, low)
+	if len(evicted) != 1 || evicted[0] != 1 {
+		t.Fatalf("expected oldest task 1 evicted got %v", evicted)
+	}
+	if st := s.Stats(ctx); st.Evicted != 1 || st.ByLevel[2] != 2 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+}
+
+func TestScheduler_CapacityEvictKeepsHigherPriority(t *testing.T) {
+	ctx := context.Background()
+	s, _ := NewDefault[int](3, WithCapacity(1), WithAdmission(AdmitEvict))
+	defer s.Close()
+
+	_, _ = s.Submit(ctx, 1, WithAttributes(levelAttrs(0)))
+	if _, err := s.Submit(ctx, 2, WithAttributes(levelAttrs(2))); err != ErrFull {
+		t.Fatalf("expected ErrFull got %v", err)
+	}
+	if _, err := s.Submit(ctx, 3, WithAttributes(levelAttrs(0))); err != nil {
+		t.Fatalf("expected same-level eviction got %v", err)
+	}
+	if lease, _ := s.Next(ctx); lease.Task != 3 {
+		t.Fatalf("expected 3 got %d", lease.Task)
+	}
+}
+
+func TestScheduler_CapacityBlock(t *testing.T) {
+	s, _ := NewDefault[int](4, WithCapacity(1), WithAdmission(AdmitBlock))
+	defer s.Close()
+	ctx := context.Background()
+	_, _ = s.Submit(ctx, 1)
+
+	tctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.Submit(tctx, 2); err != context.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded got %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Submit(ctx, 3)
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	_, _ = s.Next(ctx)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("submit: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("blocked submit not woken")
+	}
+}
+
+func TestScheduler_CapacityBlockWakesOnePerSlot(t *testing.T) {
+	s, _ := NewDefault[int](4, WithCapacity(1), WithAdmission(AdmitBlock))
+	defer s.Close()
+	sch := s.(*scheduler[int])
+	ctx := context.Background()
+	_, _ = s.Submit(ctx, 0)
+
+	done := make(chan int, 3)
+	for i := 1; i <= 3; i++ {
+		go func() {
+			if _, err := s.Submit(ctx, i); err == nil {
+				done <- i
+			}
+		}()
+		// 等第 i 个 Submit 登记后再启动下一个，保证登记顺序
+		for {
+			sch.mu.Lock()
+			n := sch.space.Len()
+			sch.mu.Unlock()
+			if n == i {
+				break
+			}
+			runtime.Gosched()
+		}
+	}
+
+	// 每腾出一个位置只放行一个等待者，且按登记顺序
+	for want := 1; want <= 3; want++ {
+		lease, _ := s.Next(ctx)
+		if got := <-done; got != want {
+			t.Fatalf("expected submit %d admitted got %d", want, got)
+		}
+		if lease.Task != want-1 {
+			t.Fatalf("expected lease %d got %d", want-1, lease.Task)
+		}
+		sch.mu.Lock()
+		n := sch.space.Len()
+		sch.mu.Unlock()
+		if n != 3-want {
+			t.Fatalf("expected %d blocked submits got %d", 3-want, n)
+		}
+	}
+}
+
+func TestNew_EvictCallbackTypeMismatch(t *testing.T) {
+	_, err := NewDefault[int](4, WithCapacity(1), WithAdmission(AdmitEvict),
+		WithEvictCallback(func(Token, string) {}))
+	if err != ErrEvictCallback {
+		t.Fatalf("expected ErrEvictCallback got %v", err)
+	}
+}
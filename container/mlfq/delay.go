@@ -0,0 +1,58 @@
+package mlfq
+
+import "time"
+
+// WithNotBefore 让任务在 t 之前不入队：任务先保存在定时结构中，由 Tick（或 auto-tick）在 t 之后
+// 按 Submit 时计算的 level 放入队列。时间以 WithClock 提供的时钟为准。
+//
+// 延迟中的任务计入租户配额与 WithCapacity 容量。
+func WithNotBefore(t time.Time) SubmitOption {
+	return func(o *SubmitOptions) {
+		o.NotBefore = t
+	}
+}
+
+// WithDelay 让任务在 Submit 之后 d 才入队，见 WithNotBefore。
+func WithDelay(d time.Duration) SubmitOption {
+	return func(o *SubmitOptions) {
+		o.Delay = d
+	}
+}
+
+// notBefore 返回任务最早可入队的时间（NotBefore 与 now+Delay 中较晚者）。
+func (o *SubmitOptions) notBefore(now time.Time) time.Time {
+	t := o.NotBefore
+	if o.Delay > 0 {
+		if d := now.Add(o.Delay); d.After(t) {
+			t = d
+		}
+	}
+	return t
+}
+
+// readyLocked 将任务放入 level；若尚未到达 notBefore 则放入定时结构。返回任务是否已入队。
+func (s *scheduler[T]) readyLocked(st *taskState[T], level int, now time.Time) bool {
+	if st.notBefore.After(now) {
+		st.level = level
+		st.delayHandle = s.delayed.Push(st, st.notBefore)
+		return false
+	}
+	s.enqueueLocked(st, level, now)
+	return true
+}
+
+// releaseDelayedLocked 将所有到期的延迟任务放入其 level（在 Tick 中调用）。
+func (s *scheduler[T]) releaseDelayedLocked(now time.Time) {
+	for {
+		st, at, ok := s.delayed.Peek()
+		if !ok || at.After(now) {
+			return
+		}
+		s.delayed.Pop()
+		st.delayHandle = nil
+		s.enqueueLocked(st, st.level, now)
+		s.logTickMove(st)
+		s.observe(EventReleased, st, st.level, now, false)
+		s.notifyLocked()
+	}
+}
@@ -0,0 +1,100 @@
+package mlfq
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestScheduler_DelayReleasedOnTick(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Unix(100, 0)
+	s, _ := NewDefault[string](3, WithClock(func() time.Time { return clock }))
+	defer s.Close()
+
+	_, _ = s.Submit(ctx, "later", WithAttributes(levelAttrs(0)), WithDelay(2*time.Second))
+	_, _ = s.Submit(ctx, "at", WithAttributes(levelAttrs(1)), WithNotBefore(clock.Add(time.Second)))
+	if _, ok := s.Next(ctx); ok {
+		t.Fatalf("expected no ready task")
+	}
+	if st := s.Stats(ctx); st.Delayed != 2 || st.TotalLen != 0 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+
+	clock = clock.Add(time.Second)
+	s.Tick(ctx, clock)
+	if st := s.Stats(ctx); st.Delayed != 1 || st.ByLevel[1] != 1 {
+		t.Fatalf("expected one task released got %+v", st)
+	}
+	clock = clock.Add(time.Second)
+	s.Tick(ctx, clock)
+	if order := drain(t, s); len(order) != 2 || order[0] != "later" || order[1] != "at" {
+		t.Fatalf("unexpected order %v", order)
+	}
+}
+
+func TestScheduler_DelayCancelAndAutoTick(t *testing.T) {
+	ctx := context.Background()
+	start := time.Now()
+	s, _ := NewDefault[string](3, WithAutoTick(5*time.Millisecond))
+	defer s.Close()
+
+	tok, _ := s.Submit(ctx, "canceled", WithDelay(time.Hour))
+	if err := s.Cancel(ctx, tok); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	_, _ = s.Submit(ctx, "soon", WithNotBefore(start.Add(20*time.Millisecond)))
+
+	wctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	lease, err := s.NextWait(wctx)
+	if err != nil || lease.Task != "soon" {
+		t.Fatalf("expected soon got %+v err=%v", lease, err)
+	}
+	if time.Since(start) < 20*time.Millisecond {
+		t.Fatalf("released before NotBefore")
+	}
+	if st := s.Stats(ctx); st.Delayed != 0 || st.Canceled != 1 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+}
+
+func TestScheduler_DelaySnapshotJournal(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Unix(100, 0)
+	codec := WithCodec[string](JSONCodec[string]{})
+	var snap, log bytes.Buffer
+
+	s, _ := NewDefault[string](3, WithClock(func() time.Time { return clock }), codec, WithJournal(&log))
+	_, _ = s.Submit(ctx, "a", WithDelay(time.Second))
+	_, _ = s.Submit(ctx, "b", WithDelay(time.Minute))
+	if err := s.Snapshot(&snap); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	clock = clock.Add(time.Second)
+	s.Tick(ctx, clock)
+	_ = s.Close()
+
+	r, _ := NewDefault[string](3, WithClock(func() time.Time { return clock }), codec)
+	defer r.Close()
+	if err := r.Restore(bytes.NewReader(snap.Bytes())); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if st := r.Stats(ctx); st.Delayed != 2 {
+		t.Fatalf("expected 2 delayed after restore got %+v", st)
+	}
+	r.Tick(ctx, clock)
+	if st := r.Stats(ctx); st.Delayed != 1 || st.TotalLen != 1 {
+		t.Fatalf("expected a released after restore got %+v", st)
+	}
+
+	p, _ := NewDefault[string](3, WithClock(func() time.Time { return clock }), codec)
+	defer p.Close()
+	if err := p.Replay(&log); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if st := p.Stats(ctx); st.Delayed != 1 || st.TotalLen != 1 {
+		t.Fatalf("expected a released after replay got %+v", st)
+	}
+}
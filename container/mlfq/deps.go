@@ -0,0 +1,123 @@
+package mlfq
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// WithAfter 声明任务的前置任务：所有前置任务完成（FeedBack Finished）之前任务不会入队，
+// 完成后按 Submit 时计算的 level 入队。
+//
+// 前置任务被取消或淘汰时任务被级联取消。调度器不保留已移除任务的结果：Submit 时已不在调度器中的前置任务
+// （完成、取消或淘汰）都视为已满足；已取消但仍在等待 FeedBack 的 Lease 任务返回 ErrDependencyCanceled。
+// 等待前置任务的任务计入租户配额与 WithCapacity 容量。
+func WithAfter(tokens ...Token) SubmitOption {
+	return func(o *SubmitOptions) {
+		o.After = append(o.After, tokens...)
+	}
+}
+
+// tokenSeq 返回 tok 在本调度器中的分配序号；tok 不可能由本调度器分配时返回 false。
+func (s *scheduler[T]) tokenSeq(tok Token) (uint64, bool) {
+	v := uint64(tok)
+	if v < s.cfg.tokenOffset || (v-s.cfg.tokenOffset)%s.cfg.tokenStride != 0 {
+		return 0, false
+	}
+	return (v - s.cfg.tokenOffset) / s.cfg.tokenStride, true
+}
+
+// issuedLocked 判断 tok 是否是本调度器已分配过的 token。
+func (s *scheduler[T]) issuedLocked(tok Token) bool {
+	seq, ok := s.tokenSeq(tok)
+	return ok && seq >= 1 && seq <= s.nextToken
+}
+
+// checkAfterLocked 校验新任务的前置任务：不能是即将分配给新任务的 token，必须已分配，且未被取消。
+//
+// 前置任务只能是已分配的 token，因此唯一可能的环是依赖自身；调用方须在检查之后、释放锁之前分配 token。
+func (s *scheduler[T]) checkAfterLocked(after []Token) error {
+	next := Token((s.nextToken+1)*s.cfg.tokenStride + s.cfg.tokenOffset)
+	for _, t := range after {
+		if t == next {
+			return ErrCycle
+		}
+		if !s.issuedLocked(t) {
+			return ErrUnknownToken
+		}
+		if st, ok := s.states[t]; ok && st.canceled {
+			return ErrDependencyCanceled
+		}
+	}
+	return nil
+}
+
+// holdLocked 登记 st 对 after 中仍存活任务的依赖；返回 true 表示任务需要等待（不入队）。
+func (s *scheduler[T]) holdLocked(st *taskState[T], after []Token) bool {
+	for _, t := range after {
+		pre, ok := s.states[t]
+		if !ok || pre == st || slices.Contains(st.after, t) {
+			continue
+		}
+		pre.dependents = append(pre.dependents, st)
+		st.after = append(st.after, t)
+		st.waitingOn++
+	}
+	if st.waitingOn == 0 {
+		return false
+	}
+	st.held = true
+	s.held++
+	return true
+}
+
+// liveAfterLocked 返回 st 仍未完成的前置任务（用于快照与日志）。
+func (s *scheduler[T]) liveAfterLocked(st *taskState[T]) []Token {
+	var out []Token
+	for _, t := range st.after {
+		if _, ok := s.states[t]; ok {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// releaseDependentsLocked 在 st 完成后递减其后继的等待计数，计数归零的后继入队。
+func (s *scheduler[T]) releaseDependentsLocked(st *taskState[T], now time.Time) {
+	deps := st.dependents
+	st.dependents = nil
+	for _, dep := range deps {
+		if s.states[dep.token] != dep || !dep.held {
+			continue
+		}
+		dep.waitingOn--
+		if dep.waitingOn > 0 {
+			continue
+		}
+		dep.held = false
+		s.held--
+		if s.readyLocked(dep, dep.level, now) {
+			s.observe(EventReleased, dep, dep.level, now, false)
+			s.notifyLocked()
+		}
+	}
+}
+
+// cancelDependentsLocked 级联取消 st 的所有后继（st 被取消或淘汰时调用）。
+func (s *scheduler[T]) cancelDependentsLocked(st *taskState[T]) {
+	deps := st.dependents
+	st.dependents = nil
+	if len(deps) == 0 {
+		return
+	}
+	now := s.cfg.now()
+	for _, dep := range deps {
+		if s.states[dep.token] != dep {
+			continue
+		}
+		s.dropLocked(dep, context.Canceled)
+		s.canceled++
+		dep.ts.canceled++
+		s.observe(EventCanceled, dep, dep.level, now, false)
+	}
+}
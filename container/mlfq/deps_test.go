@@ -0,0 +1,200 @@
+package mlfq
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScheduler_AfterReleasesOnFinish(t *testing.T) {
+	ctx := context.Background()
+	s, _ := NewDefault[string](3)
+	defer s.Close()
+
+	a, _ := s.Submit(ctx, "a", WithAttributes(levelAttrs(2)))
+	b, _ := s.Submit(ctx, "b", WithAttributes(levelAttrs(2)))
+	_, _ = s.Submit(ctx, "c", WithAttributes(levelAttrs(0)), WithAfter(a, b))
+	if st := s.Stats(ctx); st.Held != 1 || st.TotalLen != 2 {
+		t.Fatalf("expected c held got %+v", st)
+	}
+
+	lease, _ := s.Next(ctx)
+	_ = s.FeedBack(ctx, lease.Token, Feedback{Finished: true})
+	if st := s.Stats(ctx); st.Held != 1 {
+		t.Fatalf("expected c still held got held=%d", st.Held)
+	}
+	// 未完成（降级）的反馈不释放后继。
+	lease, _ = s.Next(ctx)
+	_ = s.FeedBack(ctx, lease.Token, Feedback{RanFor: time.Second})
+	if st := s.Stats(ctx); st.Held != 1 {
+		t.Fatalf("expected c still held got held=%d", st.Held)
+	}
+	lease, _ = s.Next(ctx)
+	_ = s.FeedBack(ctx, lease.Token, Feedback{Finished: true})
+
+	lease, ok := s.Next(ctx)
+	if !ok || lease.Task != "c" || lease.Level != 0 {
+		t.Fatalf("expected c at level 0 got %+v ok=%v", lease, ok)
+	}
+	if st := s.Stats(ctx); st.Held != 0 {
+		t.Fatalf("expected no held task got %d", st.Held)
+	}
+}
+
+func TestScheduler_AfterCascadesCancel(t *testing.T) {
+	ctx := context.Background()
+	s, _ := NewDefault[string](3)
+	defer s.Close()
+
+	a, _ := s.Submit(ctx, "a")
+	b, _ := s.Submit(ctx, "b", WithAfter(a))
+	_, _ = s.Submit(ctx, "c", WithAfter(b))
+	done, _ := s.Submit(ctx, "done")
+	_, _ = s.Submit(ctx, "d", WithAfter(done))
+
+	if err := s.Cancel(ctx, a); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	st := s.Stats(ctx)
+	if st.Held != 1 || st.Canceled != 3 {
+		t.Fatalf("expected b and c canceled got %+v", st)
+	}
+	if order := drain(t, s); len(order) != 2 || order[0] != "done" || order[1] != "d" {
+		t.Fatalf("unexpected order %v", order)
+	}
+}
+
+func TestScheduler_AfterCanceledPrerequisite(t *testing.T) {
+	ctx := context.Background()
+	s, _ := NewDefault[string](3)
+	defer s.Close()
+
+	// 已取消、仍在等待 FeedBack 的 Lease 任务视为失败。
+	a, _ := s.Submit(ctx, "a")
+	lease, _ := s.Next(ctx)
+	_ = s.Cancel(ctx, a)
+	if _, err := s.Submit(ctx, "b", WithAfter(a)); !errors.Is(err, ErrDependencyCanceled) {
+		t.Fatalf("expected ErrDependencyCanceled for canceled lease got %v", err)
+	}
+	_ = s.FeedBack(ctx, lease.Token, Feedback{Epoch: lease.Epoch, Finished: true})
+
+	// 已移除的前置任务不再保留结果，与已完成的前置任务一样视为已满足。
+	if _, err := s.Submit(ctx, "c", WithAfter(a)); err != nil {
+		t.Fatalf("submit after removed prerequisite: %v", err)
+	}
+	if st := s.Stats(ctx); st.Submitted != 2 || st.Held != 0 || st.TotalLen != 1 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+}
+
+func TestScheduler_AfterRecheckedAfterBlock(t *testing.T) {
+	ctx := context.Background()
+	s, _ := NewDefault[string](3, WithCapacity(1), WithAdmission(AdmitBlock))
+	defer s.Close()
+
+	pre, _ := s.Submit(ctx, "pre")
+	lease, _ := s.Next(ctx)
+	_, _ = s.Submit(ctx, "fill")
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Submit(ctx, "dep", WithAfter(pre))
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	// 等待期间前置任务被取消：被唤醒后重新检查依赖。
+	_ = s.Cancel(ctx, pre)
+	_, _ = s.Next(ctx)
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrDependencyCanceled) {
+			t.Fatalf("expected ErrDependencyCanceled got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("blocked submit not woken")
+	}
+	_ = s.FeedBack(ctx, lease.Token, Feedback{Epoch: lease.Epoch})
+}
+
+func TestScheduler_AfterRejectsCycle(t *testing.T) {
+	ctx := context.Background()
+	s, _ := NewDefault[string](3)
+	defer s.Close()
+
+	a, _ := s.Submit(ctx, "a")
+	if _, err := s.Submit(ctx, "b", WithAfter(a+1)); !errors.Is(err, ErrCycle) {
+		t.Fatalf("expected ErrCycle for self dependency got %v", err)
+	}
+	if _, err := s.Submit(ctx, "b", WithAfter(a+100)); !errors.Is(err, ErrUnknownToken) {
+		t.Fatalf("expected ErrUnknownToken got %v", err)
+	}
+	if st := s.Stats(ctx); st.Submitted != 1 {
+		t.Fatalf("rejected submits should not count, got %d", st.Submitted)
+	}
+}
+
+func TestScheduler_AfterSnapshotJournal(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Unix(100, 0)
+	codec := WithCodec[string](JSONCodec[string]{})
+	var snap, log bytes.Buffer
+
+	s, _ := NewDefault[string](3, WithClock(func() time.Time { return clock }), codec, WithJournal(&log))
+	a, _ := s.Submit(ctx, "a", WithAttributes(levelAttrs(2)))
+	b, _ := s.Submit(ctx, "b", WithAttributes(levelAttrs(1)), WithAfter(a))
+	_, _ = s.Submit(ctx, "c", WithAttributes(levelAttrs(0)), WithAfter(b))
+	if err := s.Snapshot(&snap); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	lease, _ := s.Next(ctx)
+	_ = s.FeedBack(ctx, lease.Token, Feedback{Finished: true})
+	_ = s.Close()
+
+	r, _ := NewDefault[string](3, WithClock(func() time.Time { return clock }), codec)
+	defer r.Close()
+	if err := r.Restore(bytes.NewReader(snap.Bytes())); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if st := r.Stats(ctx); st.Held != 2 || st.TotalLen != 1 {
+		t.Fatalf("unexpected restored stats %+v", st)
+	}
+	if order := drain(t, r); len(order) != 3 || order[0] != "a" || order[1] != "b" || order[2] != "c" {
+		t.Fatalf("unexpected order after restore %v", order)
+	}
+
+	p, _ := NewDefault[string](3, WithClock(func() time.Time { return clock }), codec)
+	defer p.Close()
+	if err := p.Replay(&log); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if st := p.Stats(ctx); st.Held != 1 || st.ByLevel[1] != 1 {
+		t.Fatalf("expected b released after replay got %+v", st)
+	}
+}
+
+func TestSharded_AfterSameShard(t *testing.T) {
+	ctx := context.Background()
+	s := newTestSharded(t, 4)
+	defer s.Close()
+
+	a, _ := s.Submit(ctx, "a")
+	b, _ := s.Submit(ctx, "b", WithAfter(a))
+	if b%4 != a%4 {
+		t.Fatalf("expected b on the shard of a: a=%d b=%d", a, b)
+	}
+	other := a
+	for other%4 == a%4 {
+		other, _ = s.Submit(ctx, "x")
+	}
+	if _, err := s.Submit(ctx, "c", WithAfter(a, other)); !errors.Is(err, ErrCrossShard) {
+		t.Fatalf("expected ErrCrossShard got %v", err)
+	}
+	if err := s.Cancel(ctx, a); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if st := s.Stats(ctx); st.Held != 0 {
+		t.Fatalf("expected b canceled with a got held=%d", st.Held)
+	}
+}
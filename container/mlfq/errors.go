@@ -5,12 +5,40 @@ import "errors"
 var (
 	// ErrInvalidLevel 表示策略或调用方提供的 level 超出 [0, Levels)。
 	ErrInvalidLevel = errors.New("mlfq: invalid level")
-	// ErrUnknownToken 表示 FeedBack 的 token 不存在（已完成/已被移除/从未提交）。
+	// ErrUnknownToken 表示 FeedBack/Cancel 的 token 不存在（已完成/已被移除/从未提交）。
 	ErrUnknownToken = errors.New("mlfq: unknown token")
 	// ErrNotLeased 表示 token 对应任务当前不处于“已发放 Lease、等待反馈”的状态。
 	ErrNotLeased = errors.New("mlfq: token not leased")
+	// ErrLeased 表示 token 对应任务已发放 Lease，不能 Move。
+	ErrLeased = errors.New("mlfq: task leased")
+	// ErrLeaseExpired 表示 token 对应的 Lease 已超时被回收（见 WithLeaseTimeout）。
+	ErrLeaseExpired = errors.New("mlfq: lease expired")
+	// ErrTenantQuota 表示租户持有的任务数已达配额（见 WithTenantQuota）。
+	ErrTenantQuota = errors.New("mlfq: tenant quota exceeded")
+	// ErrFull 表示排队任务数已达容量上限（见 WithCapacity/WithLevelCapacity）。
+	ErrFull = errors.New("mlfq: queue full")
+	// ErrNoCodec 表示未配置或配置了与任务类型不匹配的 Codec（见 WithCodec）。
+	ErrNoCodec = errors.New("mlfq: no codec")
+	// ErrEvictCallback 表示 WithEvictCallback 的任务类型与调度器不一致。
+	ErrEvictCallback = errors.New("mlfq: evict callback type mismatch")
+	// ErrEvicted 表示任务因容量不足被淘汰（见 AdmitEvict）。
+	ErrEvicted = errors.New("mlfq: task evicted")
+	// ErrNotEmpty 表示 Restore 时调度器中已有任务。
+	ErrNotEmpty = errors.New("mlfq: scheduler not empty")
+	// ErrCycle 表示 WithAfter 声明的前置任务包含任务自身。
+	ErrCycle = errors.New("mlfq: dependency cycle")
+	// ErrDependencyCanceled 表示 WithAfter 声明的前置任务已被取消、仍在等待 FeedBack。
+	ErrDependencyCanceled = errors.New("mlfq: dependency canceled")
+	// ErrCrossShard 表示分片调度器中 WithAfter 的前置任务分布在不同分片。
+	ErrCrossShard = errors.New("mlfq: dependencies span shards")
 	// ErrNilPolicy 表示 New 传入了 nil policy。
 	ErrNilPolicy = errors.New("mlfq: nil policy")
+	// ErrNilKey 表示 NewAdaptivePolicy 的 AdaptiveConfig.Key 为 nil。
+	ErrNilKey = errors.New("mlfq: nil adaptive key")
 	// ErrClosed 表示调度器已 Close。
 	ErrClosed = errors.New("mlfq: closed")
+	// ErrNilStep 表示 Executor.Submit 传入了 nil StepFunc。
+	ErrNilStep = errors.New("mlfq: nil step func")
+	// ErrExecutorRunning 表示 Executor 已在 Run 中，不能重复启动。
+	ErrExecutorRunning = errors.New("mlfq: executor already running")
 )
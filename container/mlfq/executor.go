@@ -0,0 +1,388 @@
+package mlfq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/arknights-w/go-utils/go_pool"
+)
+
+// StepFunc 是 Executor 执行的切片任务：每次被调度时最多运行 budget，返回任务是否已完成。
+//
+// ctx 会在本次时间片（Lease.Quantum）到期、Lease 被抢占或 Executor 停止时被取消，任务应在 ctx.Done() 后尽快返回。
+// 返回非 nil err 视为任务结束（不再入队），err 会回传给完成回调。
+type StepFunc func(ctx context.Context, budget time.Duration) (done bool, err error)
+
+// ExecutorOption 用于配置 Executor。
+type ExecutorOption func(*executorConfig)
+
+type executorConfig struct {
+	pool *go_pool.Pool
+}
+
+// WithExecutorPool 指定执行切片所用的协程池（Executor 不会关闭它）；不指定时每次 Run 创建一个 workers 大小的协程池，Run 返回前关闭。
+func WithExecutorPool(pool *go_pool.Pool) ExecutorOption {
+	return func(c *executorConfig) {
+		c.pool = pool
+	}
+}
+
+// Executor 以至多 workers 并发驱动 MLFQ 的 Next → 执行 Lease.Quantum → FeedBack 循环。
+//
+// 同一时刻最多有 workers 个切片在执行；每个切片的 RanFor 由 Executor 自动测量，
+// 时间片通过 ctx deadline 强制约束。
+//
+// q 为本包创建的调度器时，直接在调度器上 Cancel 或被淘汰的任务同样会触发完成回调（错误为 context.Canceled 或 ErrEvicted）；
+// 一个调度器同一时刻只应由一个 Executor 驱动。
+type Executor struct {
+	q       Scheduler[StepFunc]
+	workers int
+	cfg     executorConfig
+
+	mu        sync.Mutex
+	callbacks map[Token]func(error)
+	// submitting 是正在进行的 Submit 数，results 暂存这期间回调尚未登记就已结束的任务结果。
+	submitting int
+	results    map[Token]error
+	// running 是已取得 Lease 的任务及其切片的取消函数（切片开始执行前为 nil），
+	// canceled 是执行中被取消、待切片返回后以对应结果回调的任务。
+	running  map[Token]context.CancelFunc
+	canceled map[Token]error
+	cancel   context.CancelFunc
+	stopped  chan struct{}
+
+	// dropped 是调度器通知的未完成即被移除的任务，由 reap 在单独的 goroutine 中回调（通知时持有调度器锁，不能获取 mu）。
+	dropMu  sync.Mutex
+	dropped []droppedTask
+	reaping bool
+}
+
+type droppedTask struct {
+	token Token
+	err   error
+}
+
+// leaseCanceler 由本包的调度器实现：取消任务的同时报告任务是否处于 Lease 中。
+type leaseCanceler interface {
+	cancel(ctx context.Context, token Token) (leased bool, err error)
+}
+
+// dropNotifier 由本包的调度器实现：任务未完成即被移除（取消、淘汰）时在调度器锁内调用 fn。
+type dropNotifier interface {
+	notifyDrop(fn func(Token, error))
+}
+
+// NewExecutor 创建一个驱动 q 的执行器；workers<=0 时按 1 处理。
+func NewExecutor(q Scheduler[StepFunc], workers int, opts ...ExecutorOption) *Executor {
+	if workers <= 0 {
+		workers = 1
+	}
+	var cfg executorConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+	e := &Executor{
+		q:         q,
+		workers:   workers,
+		cfg:       cfg,
+		callbacks: make(map[Token]func(error)),
+		results:   make(map[Token]error),
+		running:   make(map[Token]context.CancelFunc),
+		canceled:  make(map[Token]error),
+	}
+	if dn, ok := q.(dropNotifier); ok {
+		dn.notifyDrop(e.onDrop)
+	}
+	return e
+}
+
+// Submit 提交一个切片任务；done 在任务完成、出错或被 Cancel 时回调一次（可为 nil）。
+//
+// AdmitBlock 模式下 Submit 可能阻塞等待空位，期间不影响 Executor 的其他方法。
+func (e *Executor) Submit(ctx context.Context, step StepFunc, done func(err error), opts ...SubmitOption) (Token, error) {
+	if step == nil {
+		return 0, ErrNilStep
+	}
+	e.mu.Lock()
+	e.submitting++
+	e.mu.Unlock()
+
+	tok, err := e.q.Submit(ctx, step, opts...)
+
+	e.mu.Lock()
+	e.submitting--
+	var (
+		result   error
+		finished bool
+	)
+	if err == nil {
+		// 任务可能在登记回调前就已执行完毕：补发暂存的结果。
+		if result, finished = e.results[tok]; finished {
+			delete(e.results, tok)
+		} else if done != nil {
+			e.callbacks[tok] = done
+		}
+	}
+	if e.submitting == 0 {
+		// 剩余的暂存结果不属于任何 Submit（任务直接提交给了调度器）。
+		clear(e.results)
+	}
+	e.mu.Unlock()
+
+	if err != nil {
+		return 0, err
+	}
+	if finished && done != nil {
+		done(result)
+	}
+	return tok, nil
+}
+
+// Cancel 取消任务，并以 context.Canceled 触发其完成回调。
+//
+// 正在执行的切片会被取消 ctx，回调在切片返回后才触发。
+func (e *Executor) Cancel(ctx context.Context, token Token) error {
+	e.mu.Lock()
+	fn, err := e.cancelLocked(ctx, token, context.Canceled)
+	e.mu.Unlock()
+	if fn != nil {
+		fn(context.Canceled)
+	}
+	return err
+}
+
+// cancelLocked 在调度器上取消任务：任务处于 Lease 中时停止其切片，切片返回后以 result 回调；
+// 否则返回应立即以 result 调用的完成回调。
+func (e *Executor) cancelLocked(ctx context.Context, token Token, result error) (func(error), error) {
+	var (
+		leased bool
+		err    error
+	)
+	if lc, ok := e.q.(leaseCanceler); ok {
+		leased, err = lc.cancel(ctx, token)
+	} else {
+		err = e.q.Cancel(ctx, token)
+		_, leased = e.running[token]
+	}
+	if err != nil {
+		return nil, err
+	}
+	if leased {
+		e.canceled[token] = result
+		if stop := e.running[token]; stop != nil {
+			stop()
+		}
+		return nil, nil
+	}
+	return e.completeLocked(token, result), nil
+}
+
+// Run 启动消费循环并阻塞，直到 Stop、ctx 结束或调度器关闭；返回前会等待所有在途切片结束。
+//
+// 通过 Stop 停止时返回 nil；否则返回 ctx.Err() 或 ErrClosed。同一时刻只允许一个 Run。
+func (e *Executor) Run(ctx context.Context) error {
+	e.mu.Lock()
+	if e.cancel != nil {
+		e.mu.Unlock()
+		return ErrExecutorRunning
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	e.cancel = cancel
+	e.stopped = make(chan struct{})
+	stopped := e.stopped
+	e.mu.Unlock()
+
+	pool := e.cfg.pool
+	if pool == nil {
+		pool = go_pool.NewPool(e.workers, 1)
+		defer pool.Close()
+	}
+
+	sem := make(chan struct{}, e.workers)
+	var (
+		wg  sync.WaitGroup
+		err error
+	)
+loop:
+	for {
+		select {
+		case sem <- struct{}{}:
+		case <-runCtx.Done():
+			err = runCtx.Err()
+			break loop
+		}
+		lease, nerr := e.q.NextWait(runCtx)
+		if nerr != nil {
+			<-sem
+			err = nerr
+			break
+		}
+		e.mu.Lock()
+		e.running[lease.Token] = nil
+		e.mu.Unlock()
+		wg.Add(1)
+		pool.AddTask(func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			e.runSlice(runCtx, lease)
+		})
+	}
+	wg.Wait()
+
+	e.mu.Lock()
+	stoppedByUser := e.cancel == nil
+	e.cancel = nil
+	e.mu.Unlock()
+	cancel()
+	close(stopped)
+
+	if stoppedByUser {
+		return nil
+	}
+	return err
+}
+
+// Stop 停止 Run 并等待在途切片结束；未运行时直接返回。
+func (e *Executor) Stop() {
+	e.mu.Lock()
+	cancel, stopped := e.cancel, e.stopped
+	e.cancel = nil
+	e.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-stopped
+}
+
+func (e *Executor) runSlice(ctx context.Context, lease Lease[StepFunc]) {
+	var (
+		sliceCtx context.Context
+		cancel   context.CancelFunc
+	)
+	if lease.Quantum > 0 {
+		sliceCtx, cancel = context.WithTimeout(ctx, lease.Quantum)
+	} else {
+		sliceCtx, cancel = context.WithCancel(ctx)
+	}
+	if lease.Preempted != nil {
+		// 被抢占时提前取消本次切片。
+		go func() {
+			select {
+			case <-lease.Preempted:
+				cancel()
+			case <-sliceCtx.Done():
+			}
+		}()
+	}
+	e.mu.Lock()
+	e.running[lease.Token] = cancel
+	if _, ok := e.canceled[lease.Token]; ok {
+		// 切片开始前已被取消。
+		cancel()
+	}
+	e.mu.Unlock()
+
+	start := time.Now()
+	done, err := safeStep(sliceCtx, lease.Task, lease.Quantum)
+	ranFor := time.Since(start)
+	cancel()
+
+	finished := done || err != nil
+	// 持锁 FeedBack：与 Cancel 串行，保证执行中被取消的任务一定由这里回调。
+	e.mu.Lock()
+	delete(e.running, lease.Token)
+	// FeedBack 不使用 runCtx：Executor 停止时仍需把本次切片结果交还调度器。
+	ferr := e.q.FeedBack(context.Background(), lease.Token, lease.Feedback(Feedback{
+		RanFor:   ranFor,
+		Finished: finished,
+	}))
+	var fn func(error)
+	if result, ok := e.canceled[lease.Token]; ok {
+		delete(e.canceled, lease.Token)
+		finished, err = true, result
+	} else if errors.Is(ferr, ErrLeaseExpired) && finished {
+		// Lease 已被回收而任务已在本次切片完成：放弃被重新入队的副本，以本次结果回调。
+		fn, _ = e.cancelLocked(context.Background(), lease.Token, err)
+		finished = false
+	} else if errors.Is(ferr, ErrLeaseExpired) || errors.Is(ferr, ErrUnknownToken) {
+		// 任务已被重新入队（由之后的切片完成）或已被移除（由 onDrop 回调）。
+		finished = false
+	} else if ferr != nil {
+		finished, err = true, ferr
+	}
+	if finished {
+		fn = e.completeLocked(lease.Token, err)
+	}
+	e.mu.Unlock()
+	if fn != nil {
+		fn(err)
+	}
+}
+
+// onDrop 接收调度器的移除通知（持有调度器锁时调用），交给 reap 回调。
+func (e *Executor) onDrop(token Token, err error) {
+	e.dropMu.Lock()
+	defer e.dropMu.Unlock()
+	e.dropped = append(e.dropped, droppedTask{token: token, err: err})
+	if !e.reaping {
+		e.reaping = true
+		go e.reap()
+	}
+}
+
+// reap 为被移除的任务触发完成回调；已由 Cancel 或 runSlice 回调过的任务不再重复回调。
+func (e *Executor) reap() {
+	for {
+		e.dropMu.Lock()
+		batch := e.dropped
+		e.dropped = nil
+		if len(batch) == 0 {
+			e.reaping = false
+			e.dropMu.Unlock()
+			return
+		}
+		e.dropMu.Unlock()
+
+		var fns []func()
+		e.mu.Lock()
+		for _, d := range batch {
+			if _, ok := e.canceled[d.token]; ok {
+				// 切片仍在执行，由 runSlice 回调。
+				continue
+			}
+			if fn := e.completeLocked(d.token, d.err); fn != nil {
+				err := d.err
+				fns = append(fns, func() { fn(err) })
+			}
+		}
+		e.mu.Unlock()
+		for _, fn := range fns {
+			fn()
+		}
+	}
+}
+
+// completeLocked 取出任务的完成回调；Submit 尚未登记回调时暂存结果，由 Submit 补发。
+func (e *Executor) completeLocked(token Token, err error) func(error) {
+	if fn, ok := e.callbacks[token]; ok {
+		delete(e.callbacks, token)
+		return fn
+	}
+	if e.submitting > 0 {
+		e.results[token] = err
+	}
+	return nil
+}
+
+func safeStep(ctx context.Context, step StepFunc, budget time.Duration) (done bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			done, err = true, fmt.Errorf("mlfq: step panic: %v", r)
+		}
+	}()
+	return step(ctx, budget)
+}
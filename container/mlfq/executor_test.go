@@ -0,0 +1,204 @@
+package mlfq
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExecutor_RunsStepsToCompletion(t *testing.T) {
+	ctx := context.Background()
+	q, _ := New(NewDefaultPolicy[StepFunc](4, DefaultPolicyConfig{BaseQuantum: time.Millisecond}))
+	defer q.Close()
+	ex := NewExecutor(q, 4)
+
+	const tasks = 8
+	var (
+		wg    sync.WaitGroup
+		steps atomic.Int64
+	)
+	for range tasks {
+		remaining := 3
+		wg.Add(1)
+		_, err := ex.Submit(ctx, func(ctx context.Context, budget time.Duration) (bool, error) {
+			steps.Add(1)
+			// 用满时间片：等待 ctx 到期
+			<-ctx.Done()
+			remaining--
+			return remaining == 0, nil
+		}, func(err error) {
+			defer wg.Done()
+			if err != nil {
+				t.Errorf("unexpected err: %v", err)
+			}
+		})
+		if err != nil {
+			t.Fatalf("submit: %v", err)
+		}
+	}
+
+	runErr := make(chan error, 1)
+	go func() { runErr <- ex.Run(ctx) }()
+	wg.Wait()
+	ex.Stop()
+
+	if err := <-runErr; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got := steps.Load(); got != tasks*3 {
+		t.Fatalf("expected %d steps got %d", tasks*3, got)
+	}
+	if st := q.Stats(ctx); st.Finished != tasks || st.Dequeued != tasks*3 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+}
+
+func TestExecutor_ErrorAndCancel(t *testing.T) {
+	ctx := context.Background()
+	q, _ := NewDefault[StepFunc](4)
+	defer q.Close()
+	ex := NewExecutor(q, 1)
+
+	boom := errors.New("boom")
+	errs := make(chan error, 2)
+	_, _ = ex.Submit(ctx, func(context.Context, time.Duration) (bool, error) {
+		return false, boom
+	}, func(err error) { errs <- err })
+	tok, _ := ex.Submit(ctx, func(context.Context, time.Duration) (bool, error) {
+		return true, nil
+	}, func(err error) { errs <- err }, WithAttributes(Attributes{}))
+
+	if err := ex.Cancel(ctx, tok); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected canceled got %v", err)
+	}
+
+	go func() { _ = ex.Run(ctx) }()
+	defer ex.Stop()
+	select {
+	case err := <-errs:
+		if !errors.Is(err, boom) {
+			t.Fatalf("expected boom got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timeout")
+	}
+}
+
+func TestExecutor_RunTwice(t *testing.T) {
+	q, _ := NewDefault[StepFunc](2)
+	defer q.Close()
+	ex := NewExecutor(q, 1)
+
+	go func() { _ = ex.Run(context.Background()) }()
+	defer ex.Stop()
+	time.Sleep(10 * time.Millisecond)
+	if err := ex.Run(context.Background()); err != ErrExecutorRunning {
+		t.Fatalf("expected ErrExecutorRunning got %v", err)
+	}
+}
+
+func TestExecutor_CancelRunningWaitsForStep(t *testing.T) {
+	ctx := context.Background()
+	q, _ := New(NewDefaultPolicy[StepFunc](2, DefaultPolicyConfig{BaseQuantum: time.Hour}))
+	defer q.Close()
+	ex := NewExecutor(q, 1)
+
+	started := make(chan struct{})
+	var returned atomic.Bool
+	errs := make(chan error, 1)
+	tok, _ := ex.Submit(ctx, func(ctx context.Context, _ time.Duration) (bool, error) {
+		close(started)
+		<-ctx.Done()
+		returned.Store(true)
+		return false, nil
+	}, func(err error) {
+		if !returned.Load() {
+			t.Errorf("callback fired before step returned")
+		}
+		errs <- err
+	})
+
+	go func() { _ = ex.Run(ctx) }()
+	defer ex.Stop()
+	<-started
+	if err := ex.Cancel(ctx, tok); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	select {
+	case err := <-errs:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected canceled got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timeout")
+	}
+	if st := q.Stats(ctx); st.Canceled != 1 || st.TotalLen != 0 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+}
+
+func TestExecutor_SchedulerCancelAndExpiredLease(t *testing.T) {
+	ctx := context.Background()
+	var now atomic.Int64
+	clock := func() time.Time { return time.Unix(now.Load(), 0) }
+	q, _ := New(NewDefaultPolicy[StepFunc](2, DefaultPolicyConfig{BaseQuantum: time.Hour, MaxQuantum: time.Hour}),
+		WithClock(clock), WithLeaseTimeout(1))
+	defer q.Close()
+	ex := NewExecutor(q, 1)
+
+	// 直接在调度器上取消的任务同样触发完成回调。
+	errs := make(chan error, 2)
+	tok, _ := ex.Submit(ctx, func(context.Context, time.Duration) (bool, error) {
+		return true, nil
+	}, func(err error) { errs <- err })
+	if err := q.Cancel(ctx, tok); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	select {
+	case err := <-errs:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected canceled got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("callback not fired for a task canceled on the scheduler")
+	}
+
+	// Lease 被回收后切片才完成：放弃重新入队的副本，以本次结果回调一次。
+	started, release := make(chan struct{}), make(chan struct{})
+	var calls atomic.Int32
+	_, _ = ex.Submit(ctx, func(context.Context, time.Duration) (bool, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+		return true, nil
+	}, func(err error) { errs <- err })
+
+	go func() { _ = ex.Run(ctx) }()
+	defer ex.Stop()
+	<-started
+	now.Store(int64(2 * time.Hour / time.Second))
+	q.Tick(ctx, clock())
+	close(release)
+	select {
+	case err := <-errs:
+		if err != nil {
+			t.Fatalf("expected nil got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timeout")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n := calls.Load(); n != 1 || len(errs) != 0 {
+		t.Fatalf("expected one step and one callback got %d steps, %d extra callbacks", n, len(errs))
+	}
+	if st := q.Stats(ctx); st.TotalLen != 0 || st.LeaseExpired != 1 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+}
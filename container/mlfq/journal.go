@@ -0,0 +1,314 @@
+package mlfq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// WithJournal 启用追加式事件日志：Submit/FeedBack/Cancel/Update/Move/Tick 引起的状态变化以 JSON Lines 写入 w。
+//
+// 日志记录的是变化结果（新 level、移除等），Replay 时不再咨询策略，因此重放结果是确定的。
+// 启用日志需要同时提供 WithCodec；写入错误会在 Close 时返回。
+func WithJournal(w io.Writer) Option {
+	return func(c *config) {
+		c.journal = w
+	}
+}
+
+// 日志事件类型。
+const (
+	journalSubmit   = "submit"
+	journalFeedback = "feedback"
+	journalUpdate   = "update"
+	journalCancel   = "cancel"
+	journalEvict    = "evict"
+	journalTick     = "tick"
+)
+
+type journalEvent struct {
+	Op    string    `json:"op"`
+	At    time.Time `json:"at"`
+	Token Token     `json:"token,omitempty"`
+	// Level 是事件之后任务所在的 level（submit/feedback 重新入队时有效）。
+	Level   int         `json:"level,omitempty"`
+	Attrs   *Attributes `json:"attrs,omitempty"`
+	Tenant  string      `json:"tenant,omitempty"`
+	Task    []byte      `json:"task,omitempty"`
+	Removed bool        `json:"removed,omitempty"`
+	// After 是 submit 时仍未完成的前置任务，NotBefore 是任务最早入队的时间。
+	After     []Token   `json:"after,omitempty"`
+	NotBefore time.Time `json:"not_before,omitzero"`
+	// Moves/Drops 记录一次 Tick 中被移动（老化提升/回收后重新入队）与被移除的任务。
+	Moves []journalMove `json:"moves,omitempty"`
+	Drops []Token       `json:"drops,omitempty"`
+}
+
+type journalMove struct {
+	Token Token `json:"token"`
+	Level int   `json:"level"`
+}
+
+// lockedWriter 串行化多个分片对同一日志 writer 的写入。
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+type journal struct {
+	enc *json.Encoder
+	err error
+	// tick 在 Tick 执行期间累积本次的变化，Tick 结束时写出一条事件。
+	tick *journalEvent
+}
+
+func (j *journal) write(ev *journalEvent) {
+	if j.err != nil {
+		return
+	}
+	j.err = j.enc.Encode(ev)
+}
+
+func (s *scheduler[T]) logSubmit(st *taskState[T], now time.Time) {
+	if s.jr == nil {
+		return
+	}
+	data, err := s.codec.Encode(st.task)
+	if err != nil {
+		if s.jr.err == nil {
+			s.jr.err = fmt.Errorf("mlfq: encode task %d: %w", st.token, err)
+		}
+		return
+	}
+	attrs := st.attrs
+	s.jr.write(&journalEvent{
+		Op: journalSubmit, At: now, Token: st.token, Level: st.level,
+		Attrs: &attrs, Tenant: st.tenant, Task: data, After: s.liveAfterLocked(st),
+		NotBefore: st.notBefore,
+	})
+}
+
+func (s *scheduler[T]) logFeedback(st *taskState[T], now time.Time, removed bool) {
+	if s.jr == nil {
+		return
+	}
+	ev := &journalEvent{Op: journalFeedback, At: now, Token: st.token, Removed: removed}
+	if !removed {
+		attrs := st.attrs
+		ev.Level, ev.Attrs = st.level, &attrs
+	}
+	s.jr.write(ev)
+}
+
+func (s *scheduler[T]) logUpdate(st *taskState[T], now time.Time) {
+	if s.jr == nil {
+		return
+	}
+	attrs := st.attrs
+	s.jr.write(&journalEvent{Op: journalUpdate, At: now, Token: st.token, Level: st.level, Attrs: &attrs})
+}
+
+func (s *scheduler[T]) logRemove(op string, st *taskState[T]) {
+	if s.jr == nil {
+		return
+	}
+	s.jr.write(&journalEvent{Op: op, At: s.cfg.now(), Token: st.token, Removed: true})
+}
+
+func (s *scheduler[T]) logTickMove(st *taskState[T]) {
+	if s.jr != nil && s.jr.tick != nil {
+		s.jr.tick.Moves = append(s.jr.tick.Moves, journalMove{Token: st.token, Level: st.level})
+	}
+}
+
+func (s *scheduler[T]) logTickDrop(st *taskState[T]) {
+	if s.jr != nil && s.jr.tick != nil {
+		s.jr.tick.Drops = append(s.jr.tick.Drops, st.token)
+	}
+}
+
+// Replay 读取 WithJournal 写出的事件日志并依次应用到调度器，通常在 Restore 之后调用。
+//
+// 重放直接应用日志中记录的结果而不咨询策略；引用不存在 token 的事件（例如已包含在快照中）会被跳过。
+// 重放过程本身不会写入本调度器的事件日志，重放完成后建议重新 Snapshot。
+func (s *scheduler[T]) Replay(r io.Reader) error {
+	if s.codec == nil {
+		return ErrNoCodec
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+
+	jr := s.jr
+	s.jr = nil
+	defer func() { s.jr = jr }()
+
+	dec := json.NewDecoder(r)
+	for {
+		var ev journalEvent
+		if err := dec.Decode(&ev); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("mlfq: decode journal: %w", err)
+		}
+		if err := s.applyLocked(&ev); err != nil {
+			return err
+		}
+	}
+	s.notifyAllLocked()
+	return nil
+}
+
+// applyEvent 持锁应用单个日志事件（分片调度器按 token 路由后调用）。
+func (s *scheduler[T]) applyEvent(ev *journalEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	jr := s.jr
+	s.jr = nil
+	err := s.applyLocked(ev)
+	s.jr = jr
+	if err == nil {
+		s.notifyAllLocked()
+	}
+	return err
+}
+
+// routeToken 返回事件关联的任一 token，用于分片路由；事件不涉及任何任务时返回 false。
+func (ev *journalEvent) routeToken() (Token, bool) {
+	switch {
+	case ev.Op != journalTick:
+		return ev.Token, true
+	case len(ev.Moves) > 0:
+		return ev.Moves[0].Token, true
+	case len(ev.Drops) > 0:
+		return ev.Drops[0], true
+	}
+	return 0, false
+}
+
+func (s *scheduler[T]) applyLocked(ev *journalEvent) error {
+	switch ev.Op {
+	case journalSubmit:
+		if _, ok := s.states[ev.Token]; ok {
+			return nil
+		}
+		if ev.Level < 0 || ev.Level >= s.mq.Levels() {
+			return ErrInvalidLevel
+		}
+		task, err := s.codec.Decode(ev.Task)
+		if err != nil {
+			return fmt.Errorf("mlfq: decode task %d: %w", ev.Token, err)
+		}
+		var attrs Attributes
+		if ev.Attrs != nil {
+			attrs = *ev.Attrs
+		}
+		st := s.restoreLocked(ev.Token, task, attrs, ev.Tenant, ev.At)
+		st.notBefore = ev.NotBefore
+		if s.holdLocked(st, ev.After) {
+			st.level = ev.Level
+		} else {
+			s.readyLocked(st, ev.Level, ev.At)
+		}
+		s.seeTokenLocked(ev.Token)
+		s.submitted++
+	case journalFeedback, journalUpdate, journalCancel, journalEvict:
+		st, ok := s.states[ev.Token]
+		if !ok {
+			return nil
+		}
+		if ev.Removed {
+			// feedback 移除即任务完成，其余移除（cancel/evict）会级联取消后继。
+			var reason error
+			switch ev.Op {
+			case journalCancel:
+				reason = context.Canceled
+			case journalEvict:
+				reason = ErrEvicted
+			}
+			s.removeForReplayLocked(st, reason, ev.At)
+			return nil
+		}
+		if ev.Attrs != nil {
+			st.attrs = *ev.Attrs
+		}
+		return s.moveForReplayLocked(st, ev.Level, ev.At)
+	case journalTick:
+		for _, mv := range ev.Moves {
+			if st, ok := s.states[mv.Token]; ok {
+				if st.delayHandle != nil {
+					// 延迟到期：从定时结构中取出后入队。
+					s.delayed.Remove(st.delayHandle)
+					st.delayHandle = nil
+				}
+				if err := s.moveForReplayLocked(st, mv.Level, ev.At); err != nil {
+					return err
+				}
+			}
+		}
+		for _, tok := range ev.Drops {
+			if st, ok := s.states[tok]; ok {
+				s.removeForReplayLocked(st, nil, ev.At)
+			}
+		}
+	default:
+		return fmt.Errorf("mlfq: unknown journal op %q", ev.Op)
+	}
+	return nil
+}
+
+// moveForReplayLocked 将任务以入队时间 at 重新放入 level；若任务恰好处于 Lease 中，该 Lease 作废。
+func (s *scheduler[T]) moveForReplayLocked(st *taskState[T], level int, at time.Time) error {
+	if level < 0 || level >= s.mq.Levels() {
+		return ErrInvalidLevel
+	}
+	if st.held || st.delayHandle != nil {
+		st.level = level
+		return nil
+	}
+	s.detachForReplayLocked(st)
+	s.enqueueLocked(st, level, at)
+	return nil
+}
+
+// removeForReplayLocked 移除任务；reason 为 nil 时按完成处理（释放后继），否则按 reason 移除并级联取消后继。
+func (s *scheduler[T]) removeForReplayLocked(st *taskState[T], reason error, at time.Time) {
+	s.detachForReplayLocked(st)
+	if reason == nil {
+		s.releaseDependentsLocked(st, at)
+		s.removeLocked(st)
+		return
+	}
+	s.dropLocked(st, reason)
+}
+
+func (s *scheduler[T]) detachForReplayLocked(st *taskState[T]) {
+	if st.held || st.delayHandle != nil {
+		return
+	}
+	if st.leased {
+		st.leased = false
+		st.ts.leased--
+		s.releaseLease(st)
+		s.leaseEndLocked(st)
+		return
+	}
+	s.unqueueLocked(st)
+}
@@ -0,0 +1,111 @@
+package mlfq
+
+import (
+	"math/bits"
+	"time"
+)
+
+// LatencyStats 是一组时长样本的分布摘要。
+type LatencyStats struct {
+	// Count 是样本数。
+	Count uint64
+	// Mean 是平均值。
+	Mean time.Duration
+	// P50/P95/P99 是分位数（相对误差约 3%）。
+	P50 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+	// Max 是最大值（精确值）。
+	Max time.Duration
+}
+
+// histSubBits 决定每个 2 的幂区间内的子桶数（1<<histSubBits），即相对精度。
+const (
+	histSubBits  = 5
+	histSubCount = 1 << histSubBits
+)
+
+// histogram 是 HDR 风格的对数-线性直方图：[0, 32) 内每个值一个桶，
+// 之后每个 2 的幂区间再均分为 32 个子桶，因此任意值的相对误差不超过 1/32。
+//
+// 桶数组按记录到的最大值按需增长，零值可直接使用；不是线程安全的（在调度器锁内使用）。
+type histogram struct {
+	counts []uint64
+	total  uint64
+	sum    uint64
+	max    uint64
+}
+
+func histIndex(v uint64) int {
+	if v < histSubCount {
+		return int(v)
+	}
+	shift := bits.Len64(v) - histSubBits - 1
+	return (shift+1)*histSubCount + int(v>>shift) - histSubCount
+}
+
+// histUpper 返回桶 i 中的最大值。
+func histUpper(i int) uint64 {
+	if i < histSubCount {
+		return uint64(i)
+	}
+	shift := i/histSubCount - 1
+	sub := uint64(i%histSubCount + histSubCount)
+	return (sub+1)<<shift - 1
+}
+
+func (h *histogram) record(d time.Duration) {
+	v := uint64(max(d, 0))
+	i := histIndex(v)
+	if i >= len(h.counts) {
+		h.counts = append(h.counts, make([]uint64, i+1-len(h.counts))...)
+	}
+	h.counts[i]++
+	h.total++
+	h.sum += v
+	h.max = max(h.max, v)
+}
+
+// merge 将 o 的样本合并进 h。
+func (h *histogram) merge(o *histogram) {
+	if len(o.counts) > len(h.counts) {
+		h.counts = append(h.counts, make([]uint64, len(o.counts)-len(h.counts))...)
+	}
+	for i, c := range o.counts {
+		h.counts[i] += c
+	}
+	h.total += o.total
+	h.sum += o.sum
+	h.max = max(h.max, o.max)
+}
+
+// quantile 返回第 q（0..1）分位数所在桶的上界（不超过已记录的最大值）。
+func (h *histogram) quantile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	rank := uint64(q*float64(h.total) + 0.5)
+	rank = min(max(rank, 1), h.total)
+	var seen uint64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= rank {
+			return time.Duration(min(histUpper(i), h.max))
+		}
+	}
+	return time.Duration(h.max)
+}
+
+func (h *histogram) stats() LatencyStats {
+	if h.total == 0 {
+		return LatencyStats{}
+	}
+	return LatencyStats{
+		Count: h.total,
+		Mean:  time.Duration(h.sum / h.total),
+		P50:   h.quantile(0.50),
+		P95:   h.quantile(0.95),
+		P99:   h.quantile(0.99),
+		Max:   time.Duration(h.max),
+	}
+}
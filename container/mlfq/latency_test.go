@@ -0,0 +1,77 @@
+package mlfq
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHistogram_Quantiles(t *testing.T) {
+	var h histogram
+	for i := 1; i <= 10000; i++ {
+		h.record(time.Duration(i) * time.Microsecond)
+	}
+	st := h.stats()
+	if st.Count != 10000 || st.Max != 10*time.Millisecond {
+		t.Fatalf("unexpected count/max %+v", st)
+	}
+	check := func(name string, got, want time.Duration) {
+		t.Helper()
+		if diff := got - want; diff < -want/32 || diff > want/32 {
+			t.Fatalf("%s: expected ~%v got %v", name, want, got)
+		}
+	}
+	check("p50", st.P50, 5*time.Millisecond)
+	check("p95", st.P95, 9500*time.Microsecond)
+	check("p99", st.P99, 9900*time.Microsecond)
+	check("mean", st.Mean, 5000500*time.Nanosecond)
+
+	for v := uint64(0); v < 1<<20; v += 7 {
+		i := histIndex(v)
+		if histUpper(i) < v || (i > 0 && histUpper(i-1) >= v) {
+			t.Fatalf("value %d not in bucket %d", v, i)
+		}
+	}
+}
+
+func TestScheduler_LatencyStats(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Unix(100, 0)
+	s, _ := New[string](NewDefaultPolicy[string](2, DefaultPolicyConfig{
+		AgingThreshold: 5 * time.Second,
+	}), WithClock(func() time.Time { return clock }))
+	defer s.Close()
+
+	_, _ = s.Submit(ctx, "high", WithAttributes(levelAttrs(0)))
+	_, _ = s.Submit(ctx, "low", WithAttributes(levelAttrs(1)))
+	clock = clock.Add(3 * time.Second)
+	if st := s.Stats(ctx); st.HeadWait[0] != 3*time.Second || st.HeadWait[1] != 3*time.Second {
+		t.Fatalf("unexpected head wait %v", st.HeadWait)
+	}
+
+	lease, _ := s.Next(ctx)
+	clock = clock.Add(time.Second)
+	_ = s.FeedBack(ctx, lease.Token, Feedback{Finished: true})
+
+	// 老化把 low 提升到 level 0 后，其等待时间计入 level 0 且 level 1 不再有积压。
+	clock = clock.Add(2 * time.Second)
+	s.Tick(ctx, clock)
+	st := s.Stats(ctx)
+	if st.HeadWait[1] != 0 || st.HeadWait[0] != 0 {
+		t.Fatalf("expected head wait reset after promotion got %v", st.HeadWait)
+	}
+	clock = clock.Add(time.Second)
+	lease, _ = s.Next(ctx)
+	_ = s.FeedBack(ctx, lease.Token, Feedback{Finished: true})
+
+	st = s.Stats(ctx)
+	if w := st.Wait[0]; w.Count != 2 || w.Max != 3*time.Second {
+		t.Fatalf("unexpected wait stats %+v", w)
+	}
+	if w := st.Wait[1]; w.Count != 0 {
+		t.Fatalf("expected no wait samples at level 1 got %+v", w)
+	}
+	if ta := st.Turnaround[0]; ta.Count != 2 || ta.Max != 7*time.Second || ta.P50 < 3900*time.Millisecond {
+		t.Fatalf("unexpected turnaround stats %+v", ta)
+	}
+}
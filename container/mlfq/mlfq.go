@@ -2,6 +2,7 @@ package mlfq
 
 import (
 	"context"
+	"io"
 	"time"
 )
 
@@ -23,6 +24,17 @@ type Lease[T any] struct {
 	Quantum time.Duration
 	// DequeuedAt 是本次 Next 取出任务的时间戳（由内部时钟函数产生）。
 	DequeuedAt time.Time
+	// Epoch 区分同一 Token 的多次 Lease（从 1 开始），FeedBack 时通过 Feedback.Epoch 回传（见 WithLeaseTimeout）。
+	Epoch uint64
+	// Preempted 在本次 Lease 被抢占时关闭，调用方应尽快结束本次切片并 FeedBack；
+	// 未启用 WithPreemption 时为 nil（永不就绪）。
+	Preempted <-chan struct{}
+}
+
+// Feedback 返回填写了本次 Lease.Epoch 的 fb，用于 FeedBack(ctx, lease.Token, lease.Feedback(fb))。
+func (l Lease[T]) Feedback(fb Feedback) Feedback {
+	fb.Epoch = l.Epoch
+	return fb
 }
 
 // Attributes 用于描述任务属性（紧急程度、重要程度等），供策略计算初始/反馈后的优先级参考。
@@ -31,12 +43,31 @@ type Attributes struct {
 	Urgency int8
 	// Importance 表示重要程度，建议范围 0..100（越大越重要）。
 	Importance int8
+	// Deadline 是任务期望完成的截止时间（零值表示无截止时间），供 EDFPolicy 等策略使用。
+	Deadline time.Time
+}
+
+// merge 返回以 upd 中非零字段覆盖 a 的属性。
+func (a Attributes) merge(upd Attributes) Attributes {
+	if upd.Urgency != 0 {
+		a.Urgency = upd.Urgency
+	}
+	if upd.Importance != 0 {
+		a.Importance = upd.Importance
+	}
+	if !upd.Deadline.IsZero() {
+		a.Deadline = upd.Deadline
+	}
+	return a
 }
 
 // Feedback 由调用方在一次执行切片后回传。
 //
 // RanFor 是真实执行耗时；调度器会将其与上次 Next 返回的 Lease.Quantum 进行对比，用于推断是否“用满时间片”。
 type Feedback struct {
+	// Epoch 是本次反馈对应的 Lease.Epoch（可用 Lease.Feedback 自动填写），为 0 时不校验；
+	// 启用 WithLeaseTimeout 时与任务当前 Lease 不一致（旧 Lease 已被回收并重新发放）则 FeedBack 返回 ErrLeaseExpired。
+	Epoch uint64
 	// RanFor 是本次执行切片的真实耗时。
 	RanFor time.Duration
 	// Finished 表示任务已完成；为 true 时调度器会移除该任务，不再入队。
@@ -44,14 +75,24 @@ type Feedback struct {
 	// UsedFullQuantum 表示本次是否用满时间片。
 	// 若调用方不填写（false），调度器会使用 RanFor >= Lease.Quantum 的规则进行推断。
 	UsedFullQuantum bool
+	// Preempted 表示本次切片因抢占提前结束；Lease 被抢占过时调度器会自动置为 true。
+	Preempted bool
 	// Attrs 允许调用方在反馈时更新任务属性（例如紧急/重要程度的动态变化）。
-	// 若为零值，调度器默认沿用 Submit 时的属性。
+	// 按字段合并：零值字段沿用任务当前的属性（例如只更新 Urgency 不会清除 Deadline），
+	// 需要清零某个字段时使用 Update。
 	Attrs Attributes
 }
 
 type SubmitOptions struct {
 	// Attrs 是任务的初始属性（供策略计算初始 level）。
 	Attrs Attributes
+	// Tenant 是任务所属租户；同一 level 内不同租户之间轮询出队，空字符串为默认租户。
+	Tenant string
+	// After 是前置任务的 token（见 WithAfter）。
+	After []Token
+	// NotBefore/Delay 指定任务最早入队的时间（见 WithNotBefore/WithDelay）。
+	NotBefore time.Time
+	Delay     time.Duration
 }
 
 type SubmitOption func(*SubmitOptions)
@@ -62,11 +103,18 @@ func WithAttributes(attrs Attributes) SubmitOption {
 	}
 }
 
+// WithTenant 指定任务所属租户。
+func WithTenant(tenant string) SubmitOption {
+	return func(o *SubmitOptions) {
+		o.Tenant = tenant
+	}
+}
+
 // MLFQ 是对外调度接口（线程安全）。
 //
 // 约定：
 //   - Submit 入队返回 Token
-//   - Next 出队返回 Lease（含 Token）
+//   - Next 出队返回 Lease（含 Token）；队列为空时立即返回 ok=false
 //   - FeedBack 必须在 Next 后调用，且每个 Token 在被再次发放前只能反馈一次
 //   - Tick 用于老化提升/维护（可手动调用，也可 WithAutoTick 自动调用）
 //   - Close 用于停止后台 auto-tick（若启用）并将调度器标记为关闭
@@ -78,3 +126,39 @@ type MLFQ[T any] interface {
 	Stats(ctx context.Context) Stats
 	Close() error
 }
+
+// Waiter 提供阻塞出队：队列为空时阻塞，直到有任务可取、ctx 结束或调度器关闭。
+type Waiter[T any] interface {
+	NextWait(ctx context.Context) (Lease[T], error)
+	NextWaitN(ctx context.Context, n int) ([]Lease[T], error)
+}
+
+// Controller 按 Token 调整任务。
+//
+// 约定：
+//   - Cancel 取消任务：排队中的任务立即移除；已发放 Lease 的任务在下一次 FeedBack 时被丢弃
+//   - Update/Move 调整排队中任务的属性或 level，立即生效
+type Controller interface {
+	Cancel(ctx context.Context, token Token) error
+	Update(ctx context.Context, token Token, attrs Attributes) error
+	Move(ctx context.Context, token Token, level int) error
+}
+
+// Persister 保存与恢复全部任务，Replay 重放 WithJournal 写出的事件日志（均需 WithCodec）。
+type Persister interface {
+	Snapshot(w io.Writer) error
+	Restore(r io.Reader) error
+	Replay(r io.Reader) error
+}
+
+// Scheduler 是 New/NewDefault/NewSharded 返回的完整调度接口。
+//
+// 扩展能力放在独立的接口上，MLFQ 保持不变，已有的 MLFQ 实现不受影响。
+// ForgetTenant 清除空闲租户的累计统计（见 TenantStats），租户仍持有任务时返回 false。
+type Scheduler[T any] interface {
+	MLFQ[T]
+	Waiter[T]
+	Controller
+	Persister
+	ForgetTenant(tenant string) bool
+}
@@ -0,0 +1,226 @@
+package multiqueue
+
+import "github.com/arknights-w/go-utils/container/ringqueue"
+
+// entry 是子队列中的一个元素；dead 表示已被 Remove 移除，到达队头时被丢弃。
+type entry[T any] struct {
+	v    T
+	seq  uint64
+	dead bool
+}
+
+// subQueue 是单个 key 的 FIFO 子队列。
+//
+// 元素按入队顺序连续编号，队头编号为 base；Remove 只把元素标记为 dead 而不搬移其余元素，
+// 因此编号 seq 的元素始终位于下标 seq-base，按 Handle 删除为 O(1)。
+// 不变式：队头元素总是存活的（队列为空时 live==0）。
+type subQueue[T any] struct {
+	q    ringqueue.Queue[entry[T]]
+	base uint64
+	live int
+}
+
+func (s *subQueue[T]) push(v T) uint64 {
+	seq := s.base + uint64(s.q.Len())
+	s.q.PushBack(entry[T]{v: v, seq: seq})
+	s.live++
+	return seq
+}
+
+func (s *subQueue[T]) peek() (T, bool) {
+	e, ok := s.q.PeekFront()
+	return e.v, ok
+}
+
+func (s *subQueue[T]) pop() (T, bool) {
+	e, ok := s.q.PopFront()
+	if !ok {
+		return e.v, false
+	}
+	s.base++
+	s.live--
+	s.trim()
+	return e.v, true
+}
+
+// remove 移除编号为 seq 的元素；元素不存在（已出队/已移除）时返回 false。
+func (s *subQueue[T]) remove(seq uint64) (T, bool) {
+	var zero T
+	if seq < s.base || seq-s.base >= uint64(s.q.Len()) {
+		return zero, false
+	}
+	i := int(seq - s.base)
+	e, _ := s.q.At(i)
+	if e.dead || e.seq != seq {
+		return zero, false
+	}
+	s.q.Set(i, entry[T]{seq: seq, dead: true})
+	s.live--
+	s.trim()
+	return e.v, true
+}
+
+// indexFunc 返回第一个满足 match 的存活元素的编号。
+func (s *subQueue[T]) indexFunc(match func(T) bool) (uint64, bool) {
+	i := s.q.IndexFunc(func(e entry[T]) bool { return !e.dead && match(e.v) })
+	if i < 0 {
+		return 0, false
+	}
+	return s.base + uint64(i), true
+}
+
+// trim 丢弃队头的 dead 元素，维持“队头存活”的不变式。
+func (s *subQueue[T]) trim() {
+	for {
+		e, ok := s.q.PeekFront()
+		if !ok || !e.dead {
+			return
+		}
+		_, _ = s.q.PopFront()
+		s.base++
+	}
+}
+
+// levelQueue 是单个 level 的队列：按 key（如租户）拆分为多个 FIFO 子队列，key 之间轮询出队。
+//
+// 只使用默认 key（""）时退化为单个 FIFO 队列（不维护轮询顺序，走快速路径）。
+type levelQueue[T any] struct {
+	def    subQueue[T]
+	subs   map[string]*subQueue[T]
+	active ringqueue.Queue[string] // 非空子队列的轮询顺序，队头为下一个出队的 key
+	n      int
+	// pushed 是本 level 累计入队数，新建子队列以它作为起始编号，保证同一 key 的编号不会重复。
+	pushed uint64
+	// keyed 表示当前存在非默认 key 的元素，需要按 active 轮询；level 清空后复位。
+	keyed bool
+}
+
+func (l *levelQueue[T]) Len() int { return l.n }
+
+func (l *levelQueue[T]) sub(key string, create bool) *subQueue[T] {
+	if key == "" {
+		return &l.def
+	}
+	q, ok := l.subs[key]
+	if !ok && create {
+		if l.subs == nil {
+			l.subs = make(map[string]*subQueue[T])
+		}
+		q = &subQueue[T]{base: l.pushed}
+		l.subs[key] = q
+	}
+	return q
+}
+
+func (l *levelQueue[T]) LenKey(key string) int {
+	q := l.sub(key, false)
+	if q == nil {
+		return 0
+	}
+	return q.live
+}
+
+// Push 将 v 追加到 key 子队列的队尾并返回其编号。
+func (l *levelQueue[T]) Push(key string, v T) uint64 {
+	l.n++
+	l.pushed++
+	if !l.keyed {
+		if key == "" {
+			return l.def.push(v)
+		}
+		l.keyed = true
+		if l.def.live > 0 {
+			l.active.PushBack("")
+		}
+	}
+	q := l.sub(key, true)
+	if q.live == 0 {
+		l.active.PushBack(key)
+	}
+	return q.push(v)
+}
+
+func (l *levelQueue[T]) Peek() (T, bool) {
+	if !l.keyed {
+		return l.def.peek()
+	}
+	key, ok := l.active.PeekFront()
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return l.sub(key, false).peek()
+}
+
+// Pop 从队头 key 的子队列出队，并将该 key 轮转到队尾（子队列仍非空时）。
+func (l *levelQueue[T]) Pop() (T, bool) {
+	if !l.keyed {
+		v, ok := l.def.pop()
+		if ok {
+			l.n--
+		}
+		return v, ok
+	}
+	key, ok := l.active.PopFront()
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	q := l.sub(key, false)
+	v, _ := q.pop()
+	l.n--
+	if q.live > 0 {
+		l.active.PushBack(key)
+	} else {
+		l.release(key)
+	}
+	return v, true
+}
+
+// Remove 移除 key 子队列中编号为 seq 的元素。
+func (l *levelQueue[T]) Remove(key string, seq uint64) (T, bool) {
+	var zero T
+	q := l.sub(key, false)
+	if q == nil {
+		return zero, false
+	}
+	v, ok := q.remove(seq)
+	if !ok {
+		return zero, false
+	}
+	l.n--
+	if q.live == 0 && l.keyed {
+		if j := l.active.IndexFunc(func(k string) bool { return k == key }); j >= 0 {
+			_, _ = l.active.RemoveAt(j)
+		}
+		l.release(key)
+	}
+	if l.n == 0 {
+		l.keyed = false
+	}
+	return v, true
+}
+
+// RemoveFunc 在 key 的子队列中移除第一个满足 match 的元素。
+func (l *levelQueue[T]) RemoveFunc(key string, match func(T) bool) (T, bool) {
+	var zero T
+	q := l.sub(key, false)
+	if q == nil {
+		return zero, false
+	}
+	seq, ok := q.indexFunc(match)
+	if !ok {
+		return zero, false
+	}
+	return l.Remove(key, seq)
+}
+
+// release 回收已空的非默认子队列，避免大量短暂 key 占用内存；level 全空时回到快速路径。
+func (l *levelQueue[T]) release(key string) {
+	if key != "" {
+		delete(l.subs, key)
+	}
+	if l.n == 0 {
+		l.keyed = false
+	}
+}
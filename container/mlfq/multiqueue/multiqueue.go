@@ -2,7 +2,6 @@ package multiqueue
 
 import (
 	"github.com/arknights-w/go-utils/container/bitmap"
-	"github.com/arknights-w/go-utils/container/ringqueue"
 )
 
 // View 是 MultiQueue 的只读视图接口（供策略选择下一个 level）。
@@ -15,13 +14,23 @@ type View interface {
 }
 
 // MultiQueue 是多级队列：每个 level 一个 FIFO 队列，另配套位图用于快速定位非空 level。
+//
+// 每个 level 内可按 key（如租户）拆分子队列：PushKey 入队到指定 key，Pop 在 key 之间轮询，
+// 同一 key 内保持 FIFO；Push 等价于 PushKey(level, "", v)。
 type MultiQueue[T any] struct {
 	levels int
 	bm     bitmap.BitMap
-	qs     []ringqueue.Queue[T]
+	qs     []levelQueue[T]
 	total  int
 }
 
+// Handle 标识一个已入队的元素，由 PushKey 返回，用于 O(1) 的 Remove。
+type Handle struct {
+	level int
+	key   string
+	seq   uint64
+}
+
 // New 创建一个 levels 层的多级队列。
 func New[T any](levels int) *MultiQueue[T] {
 	if levels <= 0 {
@@ -30,7 +39,7 @@ func New[T any](levels int) *MultiQueue[T] {
 	return &MultiQueue[T]{
 		levels: levels,
 		bm:     bitmap.New(levels),
-		qs:     make([]ringqueue.Queue[T], levels),
+		qs:     make([]levelQueue[T], levels),
 	}
 }
 
@@ -49,23 +58,37 @@ func (m *MultiQueue[T]) MaxNonEmpty() (int, bool) { return m.bm.Max() }
 
 func (m *MultiQueue[T]) BitMapWords() []uint64 { return m.bm.Words() }
 
+// LenKey 返回 level 中 key 子队列的长度。
+func (m *MultiQueue[T]) LenKey(level int, key string) int {
+	m.mustLevel(level)
+	return m.qs[level].LenKey(key)
+}
+
 func (m *MultiQueue[T]) Push(level int, v T) {
+	m.PushKey(level, "", v)
+}
+
+// PushKey 将 v 追加到 level 中 key 子队列的队尾，返回可用于 Remove 的 Handle。
+func (m *MultiQueue[T]) PushKey(level int, key string, v T) Handle {
 	m.mustLevel(level)
 	if m.qs[level].Len() == 0 {
 		m.bm.Set(level)
 	}
-	m.qs[level].PushBack(v)
+	seq := m.qs[level].Push(key, v)
 	m.total++
+	return Handle{level: level, key: key, seq: seq}
 }
 
+// Peek 查看 level 下一个将被 Pop 的元素。
 func (m *MultiQueue[T]) Peek(level int) (T, bool) {
 	m.mustLevel(level)
-	return m.qs[level].PeekFront()
+	return m.qs[level].Peek()
 }
 
+// Pop 从 level 出队：在各 key 子队列之间轮询，每个 key 内 FIFO。
 func (m *MultiQueue[T]) Pop(level int) (T, bool) {
 	m.mustLevel(level)
-	v, ok := m.qs[level].PopFront()
+	v, ok := m.qs[level].Pop()
 	if !ok {
 		var zero T
 		return zero, false
@@ -77,6 +100,41 @@ func (m *MultiQueue[T]) Pop(level int) (T, bool) {
 	return v, true
 }
 
+// Remove 移除 h 对应的元素（保持其余元素顺序），复杂度 O(1)；元素已出队或已移除时返回 false。
+//
+// 被移除的元素只做标记，在到达队头时被丢弃，不影响 Len 与出队顺序。
+func (m *MultiQueue[T]) Remove(h Handle) (T, bool) {
+	m.mustLevel(h.level)
+	v, ok := m.qs[h.level].Remove(h.key, h.seq)
+	if !ok {
+		return v, false
+	}
+	m.total--
+	if m.qs[h.level].Len() == 0 {
+		m.bm.Clear(h.level)
+	}
+	return v, true
+}
+
+// RemoveFunc 移除 level 默认 key 子队列中第一个满足 match 的元素（保持其余元素顺序），复杂度 O(len)。
+func (m *MultiQueue[T]) RemoveFunc(level int, match func(T) bool) (T, bool) {
+	return m.RemoveKeyFunc(level, "", match)
+}
+
+// RemoveKeyFunc 移除 level 中 key 子队列里第一个满足 match 的元素，复杂度 O(LenKey(level, key))。
+func (m *MultiQueue[T]) RemoveKeyFunc(level int, key string, match func(T) bool) (T, bool) {
+	m.mustLevel(level)
+	v, ok := m.qs[level].RemoveFunc(key, match)
+	if !ok {
+		return v, false
+	}
+	m.total--
+	if m.qs[level].Len() == 0 {
+		m.bm.Clear(level)
+	}
+	return v, true
+}
+
 func (m *MultiQueue[T]) mustLevel(level int) {
 	if level < 0 || level >= m.levels {
 		panic("mlfq: level out of range")
@@ -38,3 +38,93 @@ func TestMultiQueue_MixedLevels(t *testing.T) {
 		t.Fatalf("expected min=2 got %d", min)
 	}
 }
+
+func TestMultiQueue_RemoveFunc(t *testing.T) {
+	m := New[int](3)
+	m.Push(1, 10)
+	m.Push(1, 11)
+	m.Push(2, 20)
+
+	if _, ok := m.RemoveFunc(1, func(v int) bool { return v == 99 }); ok {
+		t.Fatalf("expected not found")
+	}
+	if v, ok := m.RemoveFunc(1, func(v int) bool { return v == 11 }); !ok || v != 11 {
+		t.Fatalf("expected remove 11 got %d ok=%v", v, ok)
+	}
+	_, _ = m.RemoveFunc(1, func(v int) bool { return v == 10 })
+	if min, _ := m.MinNonEmpty(); min != 2 {
+		t.Fatalf("expected min=2 got %d", min)
+	}
+	if m.TotalLen() != 1 {
+		t.Fatalf("expected total=1 got %d", m.TotalLen())
+	}
+}
+
+func TestMultiQueue_KeyRoundRobin(t *testing.T) {
+	m := New[string](2)
+	// 租户 a 一次性压入 3 个，b/c 各 1 个：出队应在 key 间轮询，key 内 FIFO。
+	m.PushKey(0, "a", "a1")
+	m.PushKey(0, "a", "a2")
+	m.PushKey(0, "a", "a3")
+	m.PushKey(0, "b", "b1")
+	m.PushKey(0, "c", "c1")
+
+	if m.LenKey(0, "a") != 3 || m.Len(0) != 5 {
+		t.Fatalf("unexpected lens a=%d level=%d", m.LenKey(0, "a"), m.Len(0))
+	}
+	if v, _ := m.Peek(0); v != "a1" {
+		t.Fatalf("expected peek a1 got %s", v)
+	}
+	if _, ok := m.RemoveKeyFunc(0, "c", func(v string) bool { return v == "c1" }); !ok {
+		t.Fatalf("expected remove c1")
+	}
+
+	want := []string{"a1", "b1", "a2", "a3"}
+	for i, w := range want {
+		v, ok := m.Pop(0)
+		if !ok || v != w {
+			t.Fatalf("idx=%d expected %s got %s ok=%v", i, w, v, ok)
+		}
+	}
+	if _, ok := m.MinNonEmpty(); ok {
+		t.Fatalf("expected empty")
+	}
+}
+
+func TestMultiQueue_RemoveHandle(t *testing.T) {
+	m := New[int](2)
+	a := m.PushKey(1, "a", 1)
+	b := m.PushKey(1, "a", 2)
+	m.PushKey(1, "b", 3)
+	c := m.PushKey(1, "a", 4)
+
+	if v, ok := m.Remove(b); !ok || v != 2 {
+		t.Fatalf("expected remove 2 got %d ok=%v", v, ok)
+	}
+	if _, ok := m.Remove(b); ok {
+		t.Fatalf("expected second remove to fail")
+	}
+	if m.Len(1) != 3 || m.LenKey(1, "a") != 2 {
+		t.Fatalf("unexpected len %d/%d", m.Len(1), m.LenKey(1, "a"))
+	}
+	// 出队顺序不受影响：a、b 之间轮询，a 内 FIFO
+	for _, want := range []int{1, 3, 4} {
+		if v, _ := m.Pop(1); v != want {
+			t.Fatalf("expected pop %d got %d", want, v)
+		}
+	}
+	if _, ok := m.MaxNonEmpty(); ok {
+		t.Fatalf("expected empty")
+	}
+	// 已出队元素的 Handle 失效，子队列被回收重建后也不会误删新元素
+	d := m.PushKey(1, "a", 5)
+	if _, ok := m.Remove(a); ok {
+		t.Fatalf("stale handle removed an element")
+	}
+	if _, ok := m.Remove(c); ok {
+		t.Fatalf("stale handle removed an element")
+	}
+	if v, ok := m.Remove(d); !ok || v != 5 || m.TotalLen() != 0 {
+		t.Fatalf("expected remove 5 got %d ok=%v total=%d", v, ok, m.TotalLen())
+	}
+}
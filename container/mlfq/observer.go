@@ -0,0 +1,162 @@
+package mlfq
+
+import (
+	"sync"
+	"time"
+
+	"github.com/arknights-w/go-utils/container/ringqueue"
+)
+
+// EventKind 是调度事件类型。
+type EventKind uint8
+
+const (
+	// EventSubmitted 任务提交入队，Level 为初始 level。
+	EventSubmitted EventKind = iota + 1
+	// EventDequeued 任务被 Next 取出并发放 Lease。
+	EventDequeued
+	// EventFeedback 任务反馈后重新入队，OldLevel → Level。
+	EventFeedback
+	// EventAgingPromoted 任务被 Tick 老化提升，OldLevel → Level。
+	EventAgingPromoted
+	// EventLeaseExpired 任务的 Lease 超时被回收并重新入队，OldLevel → Level。
+	EventLeaseExpired
+	// EventReleased 任务的前置任务全部完成或延迟到期，任务进入 Level。
+	EventReleased
+	// EventPreempted 任务的 Lease 被抢占（Lease.Preempted 被关闭），Level 为 Lease 所在 level。
+	EventPreempted
+	// EventMoved 排队中的任务被 Update/Move 调整 level，OldLevel → Level。
+	EventMoved
+	// EventFinished 任务完成并被移除。
+	EventFinished
+	// EventCanceled 任务被 Cancel。
+	EventCanceled
+	// EventEvicted 任务被 AdmitEvict 淘汰。
+	EventEvicted
+)
+
+var eventKindNames = [...]string{
+	EventSubmitted:     "submitted",
+	EventDequeued:      "dequeued",
+	EventFeedback:      "feedback",
+	EventAgingPromoted: "aging_promoted",
+	EventLeaseExpired:  "lease_expired",
+	EventReleased:      "released",
+	EventPreempted:     "preempted",
+	EventMoved:         "moved",
+	EventFinished:      "finished",
+	EventCanceled:      "canceled",
+	EventEvicted:       "evicted",
+}
+
+func (k EventKind) String() string {
+	if int(k) < len(eventKindNames) && eventKindNames[k] != "" {
+		return eventKindNames[k]
+	}
+	return "unknown"
+}
+
+// Event 是一次调度事件。
+type Event struct {
+	Kind  EventKind
+	Token Token
+	// At 是事件发生时间（由内部时钟函数产生；Tick 触发的事件为 Tick 的 now）。
+	At time.Time
+	// Level 是事件发生后任务所在的 level；对 Finished/Canceled/Evicted 为任务最后所在的 level。
+	Level int
+	// OldLevel 是事件发生前任务所在的 level。
+	OldLevel int
+	// UsedFullQuantum 仅对 EventFeedback/EventLeaseExpired 有效，表示本次是否用满时间片。
+	UsedFullQuantum bool
+}
+
+// Observer 接收调度事件。
+//
+// Observer 在调度器锁内同步调用，应尽快返回，且不得在其中调用调度器方法。
+type Observer func(Event)
+
+// WithObserver 设置调度事件观察者。
+func WithObserver(o Observer) Option {
+	return func(c *config) {
+		c.observer = o
+	}
+}
+
+// observe 在设置了观察者时发出一个事件。
+func (s *scheduler[T]) observe(kind EventKind, st *taskState[T], oldLevel int, at time.Time, full bool) {
+	if s.cfg.observer == nil {
+		return
+	}
+	s.cfg.observer(Event{
+		Kind:            kind,
+		Token:           st.token,
+		At:              at,
+		Level:           st.level,
+		OldLevel:        oldLevel,
+		UsedFullQuantum: full,
+	})
+}
+
+// Recorder 是一个固定容量的环形事件记录器，用于测试与排查；线程安全。
+//
+// 记录数超过容量时丢弃最早的事件。用法：New(policy, WithObserver(rec.Observe))。
+type Recorder struct {
+	mu  sync.Mutex
+	q   ringqueue.Queue[Event]
+	cap int
+}
+
+// NewRecorder 创建容量为 capacity 的记录器；capacity<=0 时按 1024 处理。
+func NewRecorder(capacity int) *Recorder {
+	if capacity <= 0 {
+		capacity = 1024
+	}
+	return &Recorder{cap: capacity}
+}
+
+// Observe 记录一个事件，可直接作为 Observer 使用。
+func (r *Recorder) Observe(ev Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.q.Len() >= r.cap {
+		r.q.PopFront()
+	}
+	r.q.PushBack(ev)
+}
+
+// Events 按发生顺序返回当前记录的全部事件。
+func (r *Recorder) Events() []Event {
+	return r.filter(func(Event) bool { return true })
+}
+
+// History 按发生顺序返回指定任务的事件（仅限仍在记录中的部分）。
+func (r *Recorder) History(token Token) []Event {
+	return r.filter(func(ev Event) bool { return ev.Token == token })
+}
+
+// Len 返回当前记录的事件数。
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.q.Len()
+}
+
+// Reset 清空记录。
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.q.Reset()
+}
+
+func (r *Recorder) filter(match func(Event) bool) []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Event
+	for i := range r.q.Len() {
+		ev, _ := r.q.At(i)
+		if match(ev) {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+package mlfq
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestScheduler_ObserverHistory(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Unix(100, 0)
+	rec := NewRecorder(0)
+	s, _ := New[string](NewDefaultPolicy[string](3, DefaultPolicyConfig{
+		BaseQuantum:    10 * time.Millisecond,
+		AgingThreshold: 5 * time.Second,
+	}), WithClock(func() time.Time { return clock }), WithObserver(rec.Observe))
+	defer s.Close()
+
+	tok, _ := s.Submit(ctx, "a", WithAttributes(levelAttrs(1)))
+	other, _ := s.Submit(ctx, "b", WithAttributes(levelAttrs(2)))
+	lease, _ := s.Next(ctx)
+	_ = s.FeedBack(ctx, lease.Token, Feedback{RanFor: lease.Quantum})
+	clock = clock.Add(10 * time.Second)
+	s.Tick(ctx, clock)
+	_ = s.Cancel(ctx, other)
+	lease, _ = s.Next(ctx)
+	_ = s.FeedBack(ctx, lease.Token, Feedback{Finished: true})
+
+	got := rec.History(tok)
+	want := []Event{
+		{Kind: EventSubmitted, Level: 1, OldLevel: 1},
+		{Kind: EventDequeued, Level: 1, OldLevel: 1},
+		{Kind: EventFeedback, Level: 2, OldLevel: 1, UsedFullQuantum: true},
+		{Kind: EventAgingPromoted, Level: 1, OldLevel: 2},
+		{Kind: EventDequeued, Level: 1, OldLevel: 1},
+		{Kind: EventFinished, Level: 1, OldLevel: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d events got %+v", len(want), got)
+	}
+	for i, ev := range got {
+		w := want[i]
+		if ev.Kind != w.Kind || ev.Level != w.Level || ev.OldLevel != w.OldLevel || ev.UsedFullQuantum != w.UsedFullQuantum || ev.Token != tok {
+			t.Fatalf("idx=%d expected %v %+v got %v %+v", i, w.Kind, w, ev.Kind, ev)
+		}
+	}
+	if got[3].At != clock {
+		t.Fatalf("expected aging event at tick time got %v", got[3].At)
+	}
+
+	h := rec.History(other)
+	if len(h) == 0 || h[len(h)-1].Kind != EventCanceled {
+		t.Fatalf("expected canceled event for other got %+v", h)
+	}
+}
+
+func TestRecorder_Ring(t *testing.T) {
+	rec := NewRecorder(3)
+	for i := range 5 {
+		rec.Observe(Event{Kind: EventSubmitted, Token: Token(i)})
+	}
+	evs := rec.Events()
+	if rec.Len() != 3 || len(evs) != 3 || evs[0].Token != 2 || evs[2].Token != 4 {
+		t.Fatalf("expected last 3 events got %+v", evs)
+	}
+	if len(rec.History(0)) != 0 {
+		t.Fatalf("expected dropped event to be gone")
+	}
+	rec.Reset()
+	if rec.Len() != 0 {
+		t.Fatalf("expected empty after reset")
+	}
+	if EventAgingPromoted.String() != "aging_promoted" || EventKind(0).String() != "unknown" {
+		t.Fatalf("unexpected kind names")
+	}
+}
@@ -0,0 +1,229 @@
+package mlfq
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Codec 负责任务对象与字节之间的转换，供 Snapshot/Restore 与事件日志使用。
+type Codec[T any] interface {
+	Encode(task T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// JSONCodec 是基于 encoding/json 的 Codec 实现，适用于可 JSON 序列化的任务类型。
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) Encode(task T) ([]byte, error) { return json.Marshal(task) }
+
+func (JSONCodec[T]) Decode(data []byte) (T, error) {
+	var task T
+	err := json.Unmarshal(data, &task)
+	return task, err
+}
+
+// WithCodec 设置任务编解码器；T 需与调度器的任务类型一致，否则 New 返回 ErrNoCodec。
+func WithCodec[T any](codec Codec[T]) Option {
+	return func(c *config) {
+		c.codec = codec
+	}
+}
+
+// snapshotVersion 是快照格式版本，格式不兼容变更时递增。
+const snapshotVersion = 1
+
+type snapshotFile struct {
+	Version   int            `json:"version"`
+	NextToken uint64         `json:"next_token"`
+	Tasks     []snapshotTask `json:"tasks"`
+}
+
+type snapshotTask struct {
+	Token      Token      `json:"token"`
+	Level      int        `json:"level"`
+	Attrs      Attributes `json:"attrs"`
+	Tenant     string     `json:"tenant,omitempty"`
+	EnqueuedAt time.Time  `json:"enqueued_at"`
+	Leased     bool       `json:"leased,omitempty"`
+	Task       []byte     `json:"task"`
+
+	SubmittedAt time.Time `json:"submitted_at"`
+	// After 是等待中任务仍未完成的前置任务（见 WithAfter），NotBefore 是未入队任务最早入队的时间。
+	After     []Token   `json:"after,omitempty"`
+	NotBefore time.Time `json:"not_before,omitzero"`
+}
+
+// Snapshot 将调度器持有的全部任务（排队中与 Lease 中）序列化写入 w。
+//
+// 每个 level 内按入队顺序写出，Restore 后保持原有的 FIFO 顺序。已被 Cancel 的 Lease 任务不会写出。
+func (s *scheduler[T]) Snapshot(w io.Writer) error {
+	if s.codec == nil {
+		return ErrNoCodec
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	file, err := s.snapshotLocked()
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(file)
+}
+
+func (s *scheduler[T]) snapshotLocked() (*snapshotFile, error) {
+	file := &snapshotFile{
+		Version:   snapshotVersion,
+		NextToken: s.nextToken,
+		Tasks:     make([]snapshotTask, 0, len(s.states)),
+	}
+	add := func(st *taskState[T], after []Token) error {
+		data, err := s.codec.Encode(st.task)
+		if err != nil {
+			return fmt.Errorf("mlfq: encode task %d: %w", st.token, err)
+		}
+		var notBefore time.Time
+		if st.held || st.delayHandle != nil {
+			notBefore = st.notBefore
+		}
+		file.Tasks = append(file.Tasks, snapshotTask{
+			Token:      st.token,
+			Level:      st.level,
+			Attrs:      st.attrs,
+			Tenant:     st.tenant,
+			EnqueuedAt: st.enqueuedAt,
+			Leased:     st.leased,
+			Task:       data,
+
+			SubmittedAt: st.submittedAt,
+			After:       after,
+			NotBefore:   notBefore,
+		})
+		return nil
+	}
+	for level := range s.aging {
+		for st := s.aging[level].head; st != nil; st = st.ageNext {
+			if err := add(st, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, st := range s.states {
+		var err error
+		switch {
+		case st.leased && !st.canceled:
+			err = add(st, nil)
+		case st.held:
+			err = add(st, s.liveAfterLocked(st))
+		case st.delayHandle != nil:
+			err = add(st, nil)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return file, nil
+}
+
+// Restore 从 r 读取 Snapshot 写出的快照并恢复任务；调度器必须为空（否则返回 ErrNotEmpty）。
+//
+// 快照中处于 Lease 状态的任务在恢复时以当前时间重新入队到其所在 level；等待前置任务与延迟中的任务恢复后继续等待。
+// 恢复是原子的：任一任务解码失败时调度器保持不变。
+func (s *scheduler[T]) Restore(r io.Reader) error {
+	if s.codec == nil {
+		return ErrNoCodec
+	}
+
+	var file snapshotFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return fmt.Errorf("mlfq: decode snapshot: %w", err)
+	}
+	tasks, err := s.decodeSnapshot(&file)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	if len(s.states) > 0 {
+		return ErrNotEmpty
+	}
+	s.restoreFileLocked(&file, tasks)
+	return nil
+}
+
+// decodeSnapshot 校验快照并解码其中的全部任务（无需持锁）。
+func (s *scheduler[T]) decodeSnapshot(file *snapshotFile) ([]T, error) {
+	if file.Version != snapshotVersion {
+		return nil, fmt.Errorf("mlfq: unsupported snapshot version %d", file.Version)
+	}
+	tasks := make([]T, len(file.Tasks))
+	for i, rec := range file.Tasks {
+		if rec.Level < 0 || rec.Level >= s.mq.Levels() {
+			return nil, ErrInvalidLevel
+		}
+		task, err := s.codec.Decode(rec.Task)
+		if err != nil {
+			return nil, fmt.Errorf("mlfq: decode task %d: %w", rec.Token, err)
+		}
+		tasks[i] = task
+	}
+	return tasks, nil
+}
+
+func (s *scheduler[T]) restoreFileLocked(file *snapshotFile, tasks []T) {
+	now := s.cfg.now()
+	var held []int
+	for i, rec := range file.Tasks {
+		at := rec.EnqueuedAt
+		if rec.Leased {
+			at = now
+		}
+		st := s.restoreLocked(rec.Token, tasks[i], rec.Attrs, rec.Tenant, at)
+		if !rec.SubmittedAt.IsZero() {
+			st.submittedAt = rec.SubmittedAt
+		}
+		st.notBefore = rec.NotBefore
+		if len(rec.After) > 0 {
+			// 前置任务可能位于快照后部，全部任务重建后再登记依赖。
+			st.level = rec.Level
+			held = append(held, i)
+		} else {
+			s.readyLocked(st, rec.Level, at)
+		}
+		s.seeTokenLocked(rec.Token)
+	}
+	for _, i := range held {
+		st := s.states[file.Tasks[i].Token]
+		if !s.holdLocked(st, file.Tasks[i].After) {
+			s.readyLocked(st, st.level, now)
+		}
+	}
+	s.nextToken = max(s.nextToken, file.NextToken)
+	s.notifyAllLocked()
+}
+
+// restoreLocked 以指定 token 与提交时间重建任务状态（用于 Restore/Replay），由调用方决定入队或等待。
+func (s *scheduler[T]) restoreLocked(tok Token, task T, attrs Attributes, tenant string, at time.Time) *taskState[T] {
+	ts := s.tenantLocked(tenant)
+	st := &taskState[T]{
+		token:  tok,
+		task:   task,
+		attrs:  attrs,
+		tenant: tenant,
+		ts:     ts,
+
+		submittedAt: at,
+	}
+	st.enqueuedAt = at
+	s.states[tok] = st
+	ts.live++
+	return st
+}
@@ -0,0 +1,145 @@
+package mlfq
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+// drain 依次 Next 并以 Finished 反馈，返回出队顺序。
+func drain(t *testing.T, s MLFQ[string]) []string {
+	t.Helper()
+	ctx := context.Background()
+	var out []string
+	for {
+		lease, ok := s.Next(ctx)
+		if !ok {
+			return out
+		}
+		out = append(out, lease.Task)
+		if err := s.FeedBack(ctx, lease.Token, Feedback{Finished: true}); err != nil {
+			t.Fatalf("feedback: %v", err)
+		}
+	}
+}
+
+func TestScheduler_SnapshotRestore(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Unix(100, 0)
+	opts := []Option{WithClock(func() time.Time { return clock }), WithCodec[string](JSONCodec[string]{})}
+	s, err := NewDefault[string](3, opts...)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	defer s.Close()
+
+	_, _ = s.Submit(ctx, "leased", WithAttributes(levelAttrs(0)))
+	_, _ = s.Submit(ctx, "a", WithAttributes(levelAttrs(2)))
+	_, _ = s.Submit(ctx, "b", WithAttributes(levelAttrs(2)))
+	_, _ = s.Submit(ctx, "c", WithAttributes(levelAttrs(1)), WithTenant("t1"))
+	lease, _ := s.Next(ctx)
+	if lease.Task != "leased" {
+		t.Fatalf("expected leased task first got %q", lease.Task)
+	}
+
+	var buf bytes.Buffer
+	if err := s.Snapshot(&buf); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+
+	r, _ := NewDefault[string](3, opts...)
+	defer r.Close()
+	if err := r.Restore(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	st := r.Stats(ctx)
+	if st.TotalLen != 4 || st.ByLevel[0] != 1 || st.ByLevel[1] != 1 || st.ByLevel[2] != 2 {
+		t.Fatalf("unexpected restored stats %+v", st)
+	}
+	if st.Tenants["t1"].Queued != 1 {
+		t.Fatalf("expected tenant t1 restored got %+v", st.Tenants)
+	}
+	if err := r.Restore(bytes.NewReader(buf.Bytes())); err != ErrNotEmpty {
+		t.Fatalf("expected ErrNotEmpty got %v", err)
+	}
+
+	got := drain(t, r)
+	want := []string{"leased", "c", "a", "b"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected order %v got %v", want, got)
+		}
+	}
+	// 新 token 不与快照中的 token 冲突。
+	tok, _ := r.Submit(ctx, "d")
+	if tok <= lease.Token+3 {
+		t.Fatalf("expected fresh token got %d", tok)
+	}
+}
+
+func TestScheduler_SnapshotRequiresCodec(t *testing.T) {
+	s, _ := NewDefault[string](2)
+	defer s.Close()
+	if err := s.Snapshot(&bytes.Buffer{}); err != ErrNoCodec {
+		t.Fatalf("expected ErrNoCodec got %v", err)
+	}
+	if _, err := NewDefault[string](2, WithJournal(&bytes.Buffer{})); err != ErrNoCodec {
+		t.Fatalf("expected ErrNoCodec for journal without codec got %v", err)
+	}
+	if _, err := NewDefault[string](2, WithCodec[int](JSONCodec[int]{})); err != ErrNoCodec {
+		t.Fatalf("expected ErrNoCodec for mismatched codec got %v", err)
+	}
+}
+
+func TestScheduler_JournalReplay(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Unix(100, 0)
+	policy := func() Policy[string] {
+		return NewDefaultPolicy[string](3, DefaultPolicyConfig{AgingThreshold: 5 * time.Second})
+	}
+	codec := WithCodec[string](JSONCodec[string]{})
+	var snap, log bytes.Buffer
+
+	s, err := New(policy(), WithClock(func() time.Time { return clock }), codec, WithJournal(&log))
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	_, _ = s.Submit(ctx, "done", WithAttributes(levelAttrs(0)))
+	if err := s.Snapshot(&snap); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	// 以下事件只存在于日志中。
+	lease, _ := s.Next(ctx)
+	_ = s.FeedBack(ctx, lease.Token, Feedback{Finished: true})
+	_, _ = s.Submit(ctx, "old", WithAttributes(levelAttrs(2)))
+	canceled, _ := s.Submit(ctx, "canceled", WithAttributes(levelAttrs(2)))
+	_ = s.Cancel(ctx, canceled)
+	clock = clock.Add(10 * time.Second)
+	s.Tick(ctx, clock)
+	newTok, _ := s.Submit(ctx, "new", WithAttributes(levelAttrs(2)))
+	_ = s.Update(ctx, newTok, levelAttrs(0))
+	want := s.Stats(ctx)
+	if err := s.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	r, _ := New(policy(), WithClock(func() time.Time { return clock }), codec)
+	defer r.Close()
+	if err := r.Restore(&snap); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if err := r.Replay(&log); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	got := r.Stats(ctx)
+	for i := range want.ByLevel {
+		if got.ByLevel[i] != want.ByLevel[i] {
+			t.Fatalf("expected by level %v got %v", want.ByLevel, got.ByLevel)
+		}
+	}
+	order := drain(t, r)
+	if len(order) != 2 || order[0] != "new" || order[1] != "old" {
+		t.Fatalf("unexpected order after replay %v", order)
+	}
+}
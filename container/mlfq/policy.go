@@ -14,6 +14,18 @@ type Policy[T any] interface {
 	OnAging(now time.Time, level int, task T, enqueuedAt time.Time) (promote bool, newLevel int)
 }
 
+// Updater 是 Policy 的可选扩展：实现后，排队中的任务属性被 Update 修改时调用 OnUpdate 计算新的 level；
+// 未实现时 Update 只修改属性，任务保持当前 level。
+type Updater[T any] interface {
+	OnUpdate(now time.Time, level int, task T, old, attrs Attributes) int
+}
+
+// Finisher 是 Policy 的可选扩展：实现后，任务以 Finished 反馈时调用 OnFinish（例如清理按任务记录的历史）；
+// Finished 的反馈不会交给 OnFeedback。
+type Finisher[T any] interface {
+	OnFinish(now time.Time, level int, task T, fb Feedback)
+}
+
 // DefaultPolicyConfig 是默认策略的可调参数。
 type DefaultPolicyConfig struct {
 	// BaseQuantum 是 level=0 的基础时间片。
@@ -27,7 +39,7 @@ type DefaultPolicyConfig struct {
 // DefaultPolicy 是一个可用的基线策略：
 //   - 0 为最高优先级，level 越大优先级越低
 //   - 时间片随 level 指数增长并以 MaxQuantum 封顶
-//   - 用满时间片则倾向降级；高紧急/重要任务可适度升级
+//   - 用满时间片则倾向降级；高紧急/重要任务可适度升级；被抢占的任务保持原 level
 type DefaultPolicy[T any] struct {
 	levels int
 	cfg    DefaultPolicyConfig
@@ -84,6 +96,10 @@ func (p *DefaultPolicy[T]) OnFeedback(_ time.Time, level int, _ T, fb Feedback)
 	if fb.Finished {
 		return level, false
 	}
+	if fb.Preempted {
+		// 被抢占不是任务自身的行为，保持原 level。
+		return level, true
+	}
 	newLevel := level
 	if fb.UsedFullQuantum {
 		if newLevel < p.levels-1 {
@@ -107,3 +123,10 @@ func (p *DefaultPolicy[T]) OnAging(now time.Time, level int, _ T, enqueuedAt tim
 	}
 	return false, level
 }
+
+// OnUpdate 按新旧属性在 OnSubmit 下对应 level 的差值平移当前 level，
+// 从而保留任务此前因反馈/老化产生的升降级。
+func (p *DefaultPolicy[T]) OnUpdate(now time.Time, level int, task T, old, attrs Attributes) int {
+	delta := p.OnSubmit(now, task, SubmitOptions{Attrs: attrs}) - p.OnSubmit(now, task, SubmitOptions{Attrs: old})
+	return min(max(level+delta, 0), p.levels-1)
+}
@@ -0,0 +1,206 @@
+package mlfq
+
+import (
+	"sync"
+	"time"
+)
+
+// AdaptiveConfig 是 AdaptivePolicy 的参数。
+type AdaptiveConfig[T any, K comparable] struct {
+	DefaultPolicyConfig
+	// Key 从任务中提取跟踪运行历史所用的 key，同一任务的多次切片必须返回相同的 key（必填）。
+	Key func(T) K
+	// Alpha 是指数移动平均的平滑系数，取值 (0,1]：est = Alpha*burst + (1-Alpha)*est；默认 0.5。
+	Alpha float64
+	// Headroom 是时间片相对估计值的放大倍数，默认 1.25。
+	Headroom float64
+	// MinQuantum 是时间片下限，默认 BaseQuantum；上限为 MaxQuantum。
+	MinQuantum time.Duration
+	// MaxTracked 是同时跟踪的 key 数上限，超出时丢弃最久未使用的一条历史；默认 4096。
+	MaxTracked int
+}
+
+// adaptiveEntry 是单个任务的运行历史，同时是按最近使用排序的侵入式双向链表节点。
+type adaptiveEntry[K comparable] struct {
+	// est 是 CPU 突发长度（连续运行直到主动让出）的估计值；burst 是当前突发已运行的时长。
+	est   time.Duration
+	burst time.Duration
+
+	key        K
+	prev, next *adaptiveEntry[K]
+}
+
+// AdaptivePolicy 按任务实测的运行历史估计下一次 CPU 突发长度（近似最短剩余时间优先）：
+//   - 每次切片未用满时间片（或完成）即视为一次突发结束，以突发总时长更新指数移动平均
+//   - 用满时间片说明突发尚未结束，估计值至少提高到当前突发已运行的时长
+//   - level 取 DefaultPolicy 时间片阶梯中第一个不小于估计值的层级，估计越短优先级越高
+//   - 时间片为估计值乘以 Headroom，并限制在 [MinQuantum, MaxQuantum]
+//
+// 没有历史的任务按 DefaultPolicy 处理；任务完成后其历史由 OnFinish 移除。老化与 OnUpdate 沿用 DefaultPolicy。
+type AdaptivePolicy[T any, K comparable] struct {
+	*DefaultPolicy[T]
+	key        func(T) K
+	alpha      float64
+	headroom   float64
+	minQuantum time.Duration
+	maxTracked int
+
+	mu      sync.Mutex
+	history map[K]*adaptiveEntry[K]
+	// head 是最近使用的历史，tail 是最久未使用的历史（MaxTracked 满时被丢弃）。
+	head, tail *adaptiveEntry[K]
+}
+
+// NewAdaptivePolicy 创建自适应策略；cfg.Key 为 nil 时返回 ErrNilKey。
+func NewAdaptivePolicy[T any, K comparable](levels int, cfg AdaptiveConfig[T, K]) (*AdaptivePolicy[T, K], error) {
+	if cfg.Key == nil {
+		return nil, ErrNilKey
+	}
+	base := NewDefaultPolicy[T](levels, cfg.DefaultPolicyConfig)
+	if cfg.Alpha <= 0 || cfg.Alpha > 1 {
+		cfg.Alpha = 0.5
+	}
+	if cfg.Headroom < 1 {
+		cfg.Headroom = 1.25
+	}
+	if cfg.MinQuantum <= 0 {
+		cfg.MinQuantum = base.cfg.BaseQuantum
+	}
+	cfg.MinQuantum = min(cfg.MinQuantum, base.cfg.MaxQuantum)
+	if cfg.MaxTracked <= 0 {
+		cfg.MaxTracked = 4096
+	}
+	return &AdaptivePolicy[T, K]{
+		DefaultPolicy: base,
+		key:           cfg.Key,
+		alpha:         cfg.Alpha,
+		headroom:      cfg.Headroom,
+		minQuantum:    cfg.MinQuantum,
+		maxTracked:    cfg.MaxTracked,
+		history:       make(map[K]*adaptiveEntry[K]),
+	}, nil
+}
+
+// Estimate 返回任务当前的突发长度估计值；ok 为 false 表示尚无历史。
+func (p *AdaptivePolicy[T, K]) Estimate(task T) (est time.Duration, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e, ok := p.getLocked(p.key(task))
+	if !ok {
+		return 0, false
+	}
+	return e.est, true
+}
+
+// getLocked 查找 k 的历史并将其标记为最近使用。
+func (p *AdaptivePolicy[T, K]) getLocked(k K) (*adaptiveEntry[K], bool) {
+	e, ok := p.history[k]
+	if ok && e != p.head {
+		p.unlinkLocked(e)
+		p.pushFrontLocked(e)
+	}
+	return e, ok
+}
+
+func (p *AdaptivePolicy[T, K]) pushFrontLocked(e *adaptiveEntry[K]) {
+	e.prev, e.next = nil, p.head
+	if p.head != nil {
+		p.head.prev = e
+	} else {
+		p.tail = e
+	}
+	p.head = e
+}
+
+func (p *AdaptivePolicy[T, K]) unlinkLocked(e *adaptiveEntry[K]) {
+	if e.prev == nil {
+		p.head = e.next
+	} else {
+		e.prev.next = e.next
+	}
+	if e.next == nil {
+		p.tail = e.prev
+	} else {
+		e.next.prev = e.prev
+	}
+	e.prev, e.next = nil, nil
+}
+
+// removeLocked 移除 k 的历史。
+func (p *AdaptivePolicy[T, K]) removeLocked(k K) {
+	if e, ok := p.history[k]; ok {
+		p.unlinkLocked(e)
+		delete(p.history, k)
+	}
+}
+
+// levelFor 返回时间片阶梯中第一个不小于 est 的 level。
+func (p *AdaptivePolicy[T, K]) levelFor(est time.Duration) int {
+	var zero T
+	for level := 0; level < p.levels-1; level++ {
+		if p.DefaultPolicy.Quantum(time.Time{}, level, zero) >= est {
+			return level
+		}
+	}
+	return p.levels - 1
+}
+
+func (p *AdaptivePolicy[T, K]) OnSubmit(now time.Time, task T, opts SubmitOptions) int {
+	if est, ok := p.Estimate(task); ok {
+		return p.levelFor(est)
+	}
+	return p.DefaultPolicy.OnSubmit(now, task, opts)
+}
+
+func (p *AdaptivePolicy[T, K]) Quantum(now time.Time, level int, task T) time.Duration {
+	est, ok := p.Estimate(task)
+	if !ok {
+		return p.DefaultPolicy.Quantum(now, level, task)
+	}
+	q := time.Duration(float64(est) * p.headroom)
+	return min(max(q, p.minQuantum), p.cfg.MaxQuantum)
+}
+
+func (p *AdaptivePolicy[T, K]) OnFeedback(now time.Time, level int, task T, fb Feedback) (int, bool) {
+	k := p.key(task)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if fb.Finished {
+		p.removeLocked(k)
+		return level, false
+	}
+	e, ok := p.getLocked(k)
+	if !ok {
+		if len(p.history) >= p.maxTracked {
+			p.removeLocked(p.tail.key)
+		}
+		e = &adaptiveEntry[K]{key: k}
+		p.history[k] = e
+		p.pushFrontLocked(e)
+	}
+	e.burst += fb.RanFor
+	switch {
+	case fb.UsedFullQuantum || fb.Preempted:
+		// 突发尚未结束：估计值至少为已运行的时长。
+		e.est = max(e.est, e.burst)
+	case !ok:
+		e.est = e.burst
+		e.burst = 0
+	default:
+		e.est = time.Duration(p.alpha*float64(e.burst) + (1-p.alpha)*float64(e.est))
+		e.burst = 0
+	}
+	if fb.Preempted {
+		// 被抢占不是任务自身的行为，保持原 level。
+		return level, true
+	}
+	return p.levelFor(e.est), true
+}
+
+// OnFinish 实现 Finisher：任务完成后移除其历史。
+func (p *AdaptivePolicy[T, K]) OnFinish(_ time.Time, _ int, task T, _ Feedback) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.removeLocked(p.key(task))
+}
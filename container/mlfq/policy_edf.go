@@ -0,0 +1,101 @@
+package mlfq
+
+import "time"
+
+// EDFConfig 是 EDFPolicy 的参数。
+type EDFConfig struct {
+	DefaultPolicyConfig
+	// Granularity 是每个 level 对应的剩余时间跨度：level = (Deadline-now)/Granularity。
+	Granularity time.Duration
+	// BestEffortLevels 是留给无截止时间任务的 level 数（位于最底部），默认 1。
+	BestEffortLevels int
+}
+
+// EDFPolicy 是（分桶近似的）最早截止时间优先策略，由 Attributes.Deadline 驱动。
+//
+//   - 有截止时间的任务按剩余时间放入 level 0..levels-BestEffortLevels-1（剩余越少 level 越小），
+//     最后 BestEffortLevels 层留给无截止时间的任务
+//   - 任务每等待一个 Granularity，OnAging 将其提升一层，使 level 始终近似反映剩余时间
+//   - 无截止时间的任务按 DefaultPolicy 反馈与老化，但只在底部的 level 之间移动，不会越过有截止时间的任务
+//   - 同一 level 内按 FIFO 出队，因此截止时间的分辨率为 Granularity
+type EDFPolicy[T any] struct {
+	*DefaultPolicy[T]
+	gran       time.Duration
+	bestEffort int
+}
+
+func NewEDFPolicy[T any](levels int, cfg EDFConfig) *EDFPolicy[T] {
+	if cfg.Granularity <= 0 {
+		cfg.Granularity = 100 * time.Millisecond
+	}
+	if cfg.BestEffortLevels <= 0 {
+		cfg.BestEffortLevels = 1
+	}
+	return &EDFPolicy[T]{
+		DefaultPolicy: NewDefaultPolicy[T](levels, cfg.DefaultPolicyConfig),
+		gran:          cfg.Granularity,
+		bestEffort:    cfg.BestEffortLevels,
+	}
+}
+
+// deadlineLevels 返回可用于有截止时间任务的 level 数。
+func (p *EDFPolicy[T]) deadlineLevels() int {
+	return max(p.levels-p.bestEffort, 1)
+}
+
+// clampBestEffort 将无截止时间任务的 level 限制在底部的 level 内。
+func (p *EDFPolicy[T]) clampBestEffort(level int) int {
+	return min(max(level, p.deadlineLevels()), p.levels-1)
+}
+
+func (p *EDFPolicy[T]) levelFor(now time.Time, attrs Attributes) int {
+	if attrs.Deadline.IsZero() {
+		return p.clampBestEffort(0)
+	}
+	remaining := attrs.Deadline.Sub(now)
+	if remaining <= 0 {
+		return 0
+	}
+	return min(int(remaining/p.gran), p.deadlineLevels()-1)
+}
+
+func (p *EDFPolicy[T]) OnSubmit(now time.Time, _ T, opts SubmitOptions) int {
+	return p.levelFor(now, opts.Attrs)
+}
+
+func (p *EDFPolicy[T]) OnFeedback(now time.Time, level int, task T, fb Feedback) (int, bool) {
+	if fb.Finished {
+		return level, false
+	}
+	if fb.Attrs.Deadline.IsZero() {
+		newLevel, requeue := p.DefaultPolicy.OnFeedback(now, level, task, fb)
+		return p.clampBestEffort(newLevel), requeue
+	}
+	return p.levelFor(now, fb.Attrs), true
+}
+
+func (p *EDFPolicy[T]) OnAging(now time.Time, level int, task T, enqueuedAt time.Time) (bool, int) {
+	if level <= 0 {
+		return false, level
+	}
+	if level >= p.deadlineLevels() {
+		// 无截止时间的任务沿用默认老化，但不进入有截止时间任务的 level。
+		promote, newLevel := p.DefaultPolicy.OnAging(now, level, task, enqueuedAt)
+		if newLevel = p.clampBestEffort(newLevel); !promote || newLevel == level {
+			return false, level
+		}
+		return true, newLevel
+	}
+	steps := int(now.Sub(enqueuedAt) / p.gran)
+	if steps <= 0 {
+		return false, level
+	}
+	return true, max(level-steps, 0)
+}
+
+func (p *EDFPolicy[T]) OnUpdate(now time.Time, level int, task T, old, attrs Attributes) int {
+	if attrs.Deadline.IsZero() && old.Deadline.IsZero() {
+		return p.clampBestEffort(p.DefaultPolicy.OnUpdate(now, level, task, old, attrs))
+	}
+	return p.levelFor(now, attrs)
+}
@@ -0,0 +1,175 @@
+package mlfq
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ShareConfig 是按权重在 level 之间分配调度机会的策略（Lottery/Stride/WeightedFair）的参数。
+//
+// 这些策略只替换 PickNext，时间片、反馈升降级与老化沿用 DefaultPolicy。
+type ShareConfig struct {
+	DefaultPolicyConfig
+	// Weights 是每个 level 的权重（票数），下标对应 level；缺省或 <=0 时按 levels-level 处理（高优先级权重更大）。
+	Weights []int
+	// Seed 是 LotteryPolicy 的随机种子；0 表示使用当前时间。
+	Seed int64
+}
+
+func (c ShareConfig) weights(levels int) []int {
+	ws := make([]int, levels)
+	for i := range ws {
+		if i < len(c.Weights) && c.Weights[i] > 0 {
+			ws[i] = c.Weights[i]
+		} else {
+			ws[i] = levels - i
+		}
+	}
+	return ws
+}
+
+// LotteryPolicy 是彩票调度：每次 PickNext 在非空 level 中按权重随机抽取。
+//
+// 长期来看各 level 获得的调度次数与其权重成正比，但短期存在随机波动。
+type LotteryPolicy[T any] struct {
+	*DefaultPolicy[T]
+
+	mu      sync.Mutex
+	weights []int
+	rnd     *rand.Rand
+}
+
+func NewLotteryPolicy[T any](levels int, cfg ShareConfig) *LotteryPolicy[T] {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &LotteryPolicy[T]{
+		DefaultPolicy: NewDefaultPolicy[T](levels, cfg.DefaultPolicyConfig),
+		weights:       cfg.weights(levels),
+		rnd:           rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (p *LotteryPolicy[T]) PickNext(_ time.Time, q QueueView) (int, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	total := 0
+	for level, w := range p.weights {
+		if q.Len(level) > 0 {
+			total += w
+		}
+	}
+	if total == 0 {
+		return 0, false
+	}
+	ticket := p.rnd.Intn(total)
+	for level, w := range p.weights {
+		if q.Len(level) == 0 {
+			continue
+		}
+		if ticket < w {
+			return level, true
+		}
+		ticket -= w
+	}
+	return 0, false
+}
+
+// strideOne 是步长计算的分子：stride = strideOne / weight。
+const strideOne = 1 << 20
+
+// StridePolicy 是步长调度：每个 level 维护 pass 值，每次选择 pass 最小的非空 level 并令其 pass += stride。
+//
+// 与彩票调度相比结果是确定性的，任意时间窗口内的份额误差有界。
+type StridePolicy[T any] struct {
+	*DefaultPolicy[T]
+
+	mu      sync.Mutex
+	strides []uint64
+	pass    []uint64
+	global  uint64
+}
+
+func NewStridePolicy[T any](levels int, cfg ShareConfig) *StridePolicy[T] {
+	ws := cfg.weights(levels)
+	strides := make([]uint64, levels)
+	for i, w := range ws {
+		strides[i] = strideOne / uint64(w)
+	}
+	return &StridePolicy[T]{
+		DefaultPolicy: NewDefaultPolicy[T](levels, cfg.DefaultPolicyConfig),
+		strides:       strides,
+		pass:          make([]uint64, levels),
+	}
+}
+
+func (p *StridePolicy[T]) PickNext(_ time.Time, q QueueView) (int, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	best := -1
+	for level := range p.pass {
+		if q.Len(level) == 0 {
+			continue
+		}
+		// 长时间空闲的 level 重新变为非空时，从全局 pass 起步，避免凭借积累的“欠账”长期独占。
+		if p.pass[level] < p.global {
+			p.pass[level] = p.global
+		}
+		if best < 0 || p.pass[level] < p.pass[best] {
+			best = level
+		}
+	}
+	if best < 0 {
+		return 0, false
+	}
+	p.global = p.pass[best]
+	p.pass[best] += p.strides[best]
+	return best, true
+}
+
+// WeightedFairPolicy 是加权公平调度（平滑加权轮询）：每次为所有非空 level 累加其权重，
+// 选择累计值最大的 level 并减去本轮非空 level 的权重总和。
+//
+// 各 level 的调度次数与权重成正比，且同一 level 的调度在时间上尽量均匀分散。
+type WeightedFairPolicy[T any] struct {
+	*DefaultPolicy[T]
+
+	mu      sync.Mutex
+	weights []int
+	current []int
+}
+
+func NewWeightedFairPolicy[T any](levels int, cfg ShareConfig) *WeightedFairPolicy[T] {
+	return &WeightedFairPolicy[T]{
+		DefaultPolicy: NewDefaultPolicy[T](levels, cfg.DefaultPolicyConfig),
+		weights:       cfg.weights(levels),
+		current:       make([]int, levels),
+	}
+}
+
+func (p *WeightedFairPolicy[T]) PickNext(_ time.Time, q QueueView) (int, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	best, total := -1, 0
+	for level, w := range p.weights {
+		if q.Len(level) == 0 {
+			p.current[level] = 0
+			continue
+		}
+		p.current[level] += w
+		total += w
+		if best < 0 || p.current[level] > p.current[best] {
+			best = level
+		}
+	}
+	if best < 0 {
+		return 0, false
+	}
+	p.current[best] -= total
+	return best, true
+}
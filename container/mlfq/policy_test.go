@@ -0,0 +1,260 @@
+package mlfq
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// levelAttrs 返回在 3 层 DefaultPolicy 映射下落入 level 的属性（0/1/2）。
+func levelAttrs(level int) Attributes {
+	score := []int8{100, 50, 0}[level]
+	return Attributes{Urgency: score, Importance: score}
+}
+
+// runShare 在 3 个 level 各放若干任务，反复 Next→FeedBack（保持原 level），统计各 level 被选中的比例。
+func runShare(t *testing.T, p Policy[int], rounds int) []float64 {
+	t.Helper()
+	ctx := context.Background()
+	clock := time.Unix(0, 0)
+	s, err := New(p, WithClock(func() time.Time { return clock }))
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	defer s.Close()
+
+	for level := range 3 {
+		for i := range 4 {
+			_, _ = s.Submit(ctx, level*10+i, WithAttributes(levelAttrs(level)))
+		}
+	}
+	counts := make([]int, 3)
+	for range rounds {
+		lease, ok := s.Next(ctx)
+		if !ok {
+			t.Fatalf("unexpected empty")
+		}
+		counts[lease.Level]++
+		clock = clock.Add(time.Millisecond)
+		_ = s.FeedBack(ctx, lease.Token, Feedback{RanFor: 0})
+	}
+	shares := make([]float64, 3)
+	for i, c := range counts {
+		shares[i] = float64(c) / float64(rounds)
+	}
+	return shares
+}
+
+func checkShares(t *testing.T, got []float64, weights []int, tolerance float64) {
+	t.Helper()
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	for i, w := range weights {
+		want := float64(w) / float64(total)
+		if math.Abs(got[i]-want) > tolerance {
+			t.Fatalf("level %d: expected share %.3f got %.3f (all=%v)", i, want, got[i], got)
+		}
+	}
+}
+
+func TestLotteryPolicy_Share(t *testing.T) {
+	weights := []int{3, 2, 1}
+	p := NewLotteryPolicy[int](3, ShareConfig{Weights: weights, Seed: 42})
+	checkShares(t, runShare(t, p, 60000), weights, 0.01)
+}
+
+func TestStridePolicy_Share(t *testing.T) {
+	weights := []int{3, 2, 1}
+	p := NewStridePolicy[int](3, ShareConfig{Weights: weights})
+	checkShares(t, runShare(t, p, 6000), weights, 0.001)
+}
+
+func TestWeightedFairPolicy_Share(t *testing.T) {
+	weights := []int{5, 3, 2}
+	p := NewWeightedFairPolicy[int](3, ShareConfig{Weights: weights})
+	checkShares(t, runShare(t, p, 1000), weights, 0.001)
+}
+
+func TestEDFPolicy_OrdersByDeadline(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Unix(0, 0)
+	gran := 10 * time.Millisecond
+	s, _ := New(NewEDFPolicy[string](16, EDFConfig{Granularity: gran}), WithClock(func() time.Time { return clock }))
+	defer s.Close()
+
+	submit := func(name string, after time.Duration) {
+		var attrs Attributes
+		if after > 0 {
+			attrs.Deadline = clock.Add(after)
+		}
+		_, _ = s.Submit(ctx, name, WithAttributes(attrs))
+	}
+	submit("none", 0)
+	submit("d50", 50*time.Millisecond)
+	submit("d10", 10*time.Millisecond)
+	submit("d30", 30*time.Millisecond)
+
+	// t=20ms 时提交截止时间为 t=60ms 的任务；此前 d50 已等待 20ms，经 Tick 老化后应排在其之前。
+	clock = clock.Add(20 * time.Millisecond)
+	s.Tick(ctx, clock)
+	submit("d60", 40*time.Millisecond)
+
+	want := []string{"d10", "d30", "d50", "d60", "none"}
+	for i, w := range want {
+		lease, ok := s.Next(ctx)
+		if !ok || lease.Task != w {
+			t.Fatalf("idx=%d expected %s got %s ok=%v", i, w, lease.Task, ok)
+		}
+	}
+}
+
+func TestEDFPolicy_AgingStaysInBestEffortLevels(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Unix(0, 0)
+	p := NewEDFPolicy[string](4, EDFConfig{
+		DefaultPolicyConfig: DefaultPolicyConfig{AgingThreshold: 10 * time.Millisecond},
+		BestEffortLevels:    2,
+	})
+	s, _ := New(p, WithClock(func() time.Time { return clock }))
+	defer s.Close()
+
+	_, _ = s.Submit(ctx, "none")
+	lease, _ := s.Next(ctx)
+	if lease.Level != 2 {
+		t.Fatalf("expected best-effort level 2 got %d", lease.Level)
+	}
+	_ = s.FeedBack(ctx, lease.Token, Feedback{RanFor: lease.Quantum})
+	// 多次老化后只回到无截止时间任务的最高 level，不进入有截止时间任务的 level
+	for range 4 {
+		clock = clock.Add(time.Second)
+		s.Tick(ctx, clock)
+	}
+	if st := s.Stats(ctx); st.ByLevel[2] != 1 || st.AgingPromoted != 1 {
+		t.Fatalf("expected aged to level 2 only, got %+v", st.ByLevel)
+	}
+}
+
+func TestEDFPolicy_PartialFeedbackAttrsKeepDeadline(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Unix(0, 0)
+	gran := 10 * time.Millisecond
+	s, _ := New(NewEDFPolicy[string](8, EDFConfig{Granularity: gran}), WithClock(func() time.Time { return clock }))
+	defer s.Close()
+
+	_, _ = s.Submit(ctx, "d30", WithAttributes(Attributes{Deadline: clock.Add(30 * time.Millisecond)}))
+	lease, _ := s.Next(ctx)
+	// 只更新 Urgency：截止时间保留，任务仍按剩余时间回到有截止时间的 level。
+	_ = s.FeedBack(ctx, lease.Token, Feedback{RanFor: time.Millisecond, Attrs: Attributes{Urgency: 90}})
+	lease, _ = s.Next(ctx)
+	if lease.Level != 3 {
+		t.Fatalf("expected deadline level 3 got %d", lease.Level)
+	}
+}
+
+func TestAdaptivePolicy_EstimatesBurst(t *testing.T) {
+	p, err := NewAdaptivePolicy[string, string](4, AdaptiveConfig[string, string]{
+		DefaultPolicyConfig: DefaultPolicyConfig{BaseQuantum: 10 * time.Millisecond, MaxQuantum: 80 * time.Millisecond},
+		Key:                 func(s string) string { return s },
+		Alpha:               0.5,
+	})
+	if err != nil {
+		t.Fatalf("new adaptive policy: %v", err)
+	}
+	now := time.Unix(0, 0)
+	if _, ok := p.Estimate("io"); ok {
+		t.Fatalf("expected no history")
+	}
+
+	// 短突发：4ms、8ms → est = 0.5*8 + 0.5*4 = 6ms，level 0，时间片 = max(6ms*1.25, MinQuantum)。
+	_, _ = p.OnFeedback(now, 2, "io", Feedback{RanFor: 4 * time.Millisecond})
+	level, requeue := p.OnFeedback(now, 2, "io", Feedback{RanFor: 8 * time.Millisecond})
+	if est, _ := p.Estimate("io"); est != 6*time.Millisecond || level != 0 || !requeue {
+		t.Fatalf("expected est=6ms level=0 got est=%v level=%d", est, level)
+	}
+	if q := p.Quantum(now, level, "io"); q != 10*time.Millisecond {
+		t.Fatalf("expected quantum clamped to 10ms got %v", q)
+	}
+
+	// 连续用满时间片的突发：估计值随已运行时长增长，level 沿阶梯下降，时间片以 MaxQuantum 封顶。
+	level, _ = p.OnFeedback(now, 0, "cpu", Feedback{RanFor: 10 * time.Millisecond, UsedFullQuantum: true})
+	level, _ = p.OnFeedback(now, level, "cpu", Feedback{RanFor: 30 * time.Millisecond, UsedFullQuantum: true})
+	if est, _ := p.Estimate("cpu"); est != 40*time.Millisecond || level != 2 {
+		t.Fatalf("expected est=40ms level=2 got est=%v level=%d", est, level)
+	}
+	if q := p.Quantum(now, level, "cpu"); q != 50*time.Millisecond {
+		t.Fatalf("expected quantum 50ms got %v", q)
+	}
+	level, _ = p.OnFeedback(now, level, "cpu", Feedback{RanFor: 80 * time.Millisecond, UsedFullQuantum: true})
+	if q := p.Quantum(now, level, "cpu"); q != 80*time.Millisecond {
+		t.Fatalf("expected quantum clamped to 80ms got %v", q)
+	}
+
+	// 有历史的任务重新提交时直接按估计值定级；完成后历史被移除。
+	if level := p.OnSubmit(now, "io", SubmitOptions{}); level != 0 {
+		t.Fatalf("expected resubmitted io at level 0 got %d", level)
+	}
+	if _, requeue := p.OnFeedback(now, 0, "io", Feedback{Finished: true}); requeue {
+		t.Fatalf("expected finished task not requeued")
+	}
+	if _, ok := p.Estimate("io"); ok {
+		t.Fatalf("expected history removed after finish")
+	}
+}
+
+func TestAdaptivePolicy_FinishedThroughScheduler(t *testing.T) {
+	ctx := context.Background()
+	if _, err := NewAdaptivePolicy[string, string](4, AdaptiveConfig[string, string]{}); !errors.Is(err, ErrNilKey) {
+		t.Fatalf("expected ErrNilKey got %v", err)
+	}
+
+	p, _ := NewAdaptivePolicy[string, string](4, AdaptiveConfig[string, string]{
+		Key: func(s string) string { return s },
+	})
+	s, err := New[string](p)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	defer s.Close()
+
+	_, _ = s.Submit(ctx, "io")
+	lease, _ := s.Next(ctx)
+	_ = s.FeedBack(ctx, lease.Token, Feedback{Epoch: lease.Epoch, RanFor: 4 * time.Millisecond})
+	if est, ok := p.Estimate("io"); !ok || est != 4*time.Millisecond {
+		t.Fatalf("expected est=4ms got %v ok=%v", est, ok)
+	}
+	lease, _ = s.Next(ctx)
+	if err := s.FeedBack(ctx, lease.Token, Feedback{Epoch: lease.Epoch, RanFor: time.Millisecond, Finished: true}); err != nil {
+		t.Fatalf("feedback: %v", err)
+	}
+	if _, ok := p.Estimate("io"); ok {
+		t.Fatalf("expected history removed after finish")
+	}
+	if st := s.Stats(ctx); st.Finished != 1 || st.TotalLen != 0 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+}
+
+func TestAdaptivePolicy_EvictsLeastRecentlyUsed(t *testing.T) {
+	p, _ := NewAdaptivePolicy[string, string](4, AdaptiveConfig[string, string]{
+		Key:        func(s string) string { return s },
+		MaxTracked: 2,
+	})
+	now := time.Unix(0, 0)
+	fb := Feedback{RanFor: time.Millisecond}
+	_, _ = p.OnFeedback(now, 0, "a", fb)
+	_, _ = p.OnFeedback(now, 0, "b", fb)
+	_, _ = p.OnFeedback(now, 0, "a", fb)
+	_, _ = p.OnFeedback(now, 0, "c", fb)
+	if _, ok := p.Estimate("b"); ok {
+		t.Fatalf("expected least recently used b evicted")
+	}
+	for _, k := range []string{"a", "c"} {
+		if _, ok := p.Estimate(k); !ok {
+			t.Fatalf("expected %s tracked", k)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package mlfq
+
+import "time"
+
+// WithPreemption 启用抢占：当有任务进入严格更高优先级（更小）的 level 时，
+// 调度器关闭一个低优先级 Lease 的 Lease.Preempted 通知其尽快让出；minRun 是 Lease 至少运行多久才可被抢占。
+//
+// 每个排队中的最高优先级任务最多抢占一个 Lease，被抢占的总是优先级最低的 Lease 中运行最久的一个；
+// 它运行时间尚不足 minRun 时本次不抢占，之后的 Tick 会再次检查。
+// 被抢占的 Lease 仍需正常 FeedBack，调度器会自动设置 Feedback.Preempted。
+func WithPreemption(minRun time.Duration) Option {
+	return func(c *config) {
+		c.preempt = true
+		c.preemptMinRun = max(minRun, 0)
+	}
+}
+
+// runKey 是可抢占 Lease 的排序键：level 越大（优先级越低）越靠前，同 level 内发放越早越靠前。
+type runKey struct {
+	level int
+	at    time.Time
+}
+
+func runBefore(a, b runKey) bool {
+	return a.level > b.level || a.level == b.level && a.at.Before(b.at)
+}
+
+// preemptLocked 为最高优先级的排队任务抢占低优先级的 Lease。
+//
+// 只比较排队中最高的 level 与可抢占 Lease 中最低的 level，复杂度 O(本次抢占数 * log Lease 数)。
+// preemptPending 记录已发出、但尚未被后续 Next 消化的抢占：让出的 CPU 会用来取走排队任务，
+// 在此之前不为同一批排队任务重复抢占。
+func (s *scheduler[T]) preemptLocked(now time.Time) {
+	if !s.cfg.preempt || s.running.Len() == 0 {
+		return
+	}
+	best, ok := s.mq.MinNonEmpty()
+	if !ok {
+		return
+	}
+	for budget := s.mq.Len(best) - s.preemptPending; budget > 0; budget-- {
+		victim, key, ok := s.running.Peek()
+		if !ok || key.level <= best || now.Sub(key.at) < s.cfg.preemptMinRun {
+			return
+		}
+		s.running.Pop()
+		victim.runHandle = nil
+		close(victim.preempt)
+		victim.preempt = nil
+		victim.preempted = true
+		s.preemptPending++
+		s.preemptions++
+		s.observe(EventPreempted, victim, victim.level, now, false)
+	}
+}
+
+// clampPreemptLocked 在任务离开队列（取消、移动、淘汰）后将 preemptPending 截断到最高 level 的排队数：
+// 触发抢占的任务不在队列中时，对应的抢占不应继续抑制后续抢占。
+func (s *scheduler[T]) clampPreemptLocked() {
+	if s.preemptPending == 0 {
+		return
+	}
+	if best, ok := s.mq.MinNonEmpty(); ok {
+		s.preemptPending = min(s.preemptPending, s.mq.Len(best))
+	} else {
+		s.preemptPending = 0
+	}
+}
+
+// leaseStartLocked 登记新发放的 Lease（仅启用抢占时），返回其抢占通知 channel。
+func (s *scheduler[T]) leaseStartLocked(st *taskState[T]) <-chan struct{} {
+	if !s.cfg.preempt {
+		return nil
+	}
+	st.preempt = make(chan struct{})
+	st.preempted = false
+	st.runHandle = s.running.Push(st, runKey{level: st.level, at: st.lastDequeued})
+	if s.preemptPending > 0 {
+		s.preemptPending--
+	}
+	return st.preempt
+}
+
+// leaseEndLocked 在 Lease 结束（FeedBack/超时回收/重放）时撤销登记，返回该 Lease 是否被抢占过。
+func (s *scheduler[T]) leaseEndLocked(st *taskState[T]) bool {
+	if !s.cfg.preempt {
+		return false
+	}
+	if st.runHandle != nil {
+		s.running.Remove(st.runHandle)
+		st.runHandle = nil
+	}
+	st.preempt = nil
+	preempted := st.preempted
+	st.preempted = false
+	return preempted
+}
@@ -0,0 +1,100 @@
+package mlfq
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func preempted(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestScheduler_Preemption(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Unix(100, 0)
+	s, _ := NewDefault[string](3, WithClock(func() time.Time { return clock }), WithPreemption(50*time.Millisecond))
+	defer s.Close()
+
+	_, _ = s.Submit(ctx, "low", WithAttributes(levelAttrs(2)))
+	_, _ = s.Submit(ctx, "mid", WithAttributes(levelAttrs(1)))
+	mid, _ := s.Next(ctx)
+	low, _ := s.Next(ctx)
+	if mid.Preempted == nil || low.Task != "low" {
+		t.Fatalf("expected preemption channels, got %+v %+v", mid, low)
+	}
+
+	// 同 level 的新任务不抢占。
+	_, _ = s.Submit(ctx, "low2", WithAttributes(levelAttrs(2)))
+	if preempted(low.Preempted) || preempted(mid.Preempted) {
+		t.Fatalf("unexpected preemption by same-level task")
+	}
+
+	// 运行时间不足 minRun：暂不抢占，之后的 Tick 再检查。
+	_, _ = s.Submit(ctx, "high", WithAttributes(levelAttrs(0)))
+	if preempted(low.Preempted) {
+		t.Fatalf("preempted before minRun")
+	}
+	clock = clock.Add(60 * time.Millisecond)
+	s.Tick(ctx, clock)
+	// 一个高优先级任务只抢占一个 Lease：优先级最低的那个。
+	if !preempted(low.Preempted) || preempted(mid.Preempted) {
+		t.Fatalf("expected only the low lease preempted")
+	}
+	if st := s.Stats(ctx); st.Preempted != 1 {
+		t.Fatalf("expected preempted=1 got %d", st.Preempted)
+	}
+
+	// 被抢占的 Lease 不降级，且不会被重复计数。
+	s.Tick(ctx, clock)
+	_ = s.FeedBack(ctx, low.Token, Feedback{RanFor: 60 * time.Millisecond})
+	if st := s.Stats(ctx); st.Preempted != 1 || st.Demoted != 0 || st.ByLevel[2] != 2 {
+		t.Fatalf("unexpected stats after preempted feedback %+v", st)
+	}
+
+	// 让出的 CPU 取走 high 之后，新的高优先级任务才会抢占下一个 Lease（mid）。
+	if lease, _ := s.Next(ctx); lease.Task != "high" {
+		t.Fatalf("expected high got %s", lease.Task)
+	}
+	if preempted(mid.Preempted) {
+		t.Fatalf("mid preempted before high was dequeued")
+	}
+	_, _ = s.Submit(ctx, "high2", WithAttributes(levelAttrs(0)))
+	if !preempted(mid.Preempted) {
+		t.Fatalf("expected mid lease preempted")
+	}
+}
+
+func TestPolicy_PreemptedKeepsLevel(t *testing.T) {
+	p := NewDefaultPolicy[int](3, DefaultPolicyConfig{})
+	if level, _ := p.OnFeedback(time.Time{}, 1, 0, Feedback{Preempted: true, UsedFullQuantum: true}); level != 1 {
+		t.Fatalf("expected preempted task to keep level got %d", level)
+	}
+}
+
+func TestScheduler_PreemptionAfterTriggerCanceled(t *testing.T) {
+	ctx := context.Background()
+	s, _ := NewDefault[string](3, WithPreemption(0))
+	defer s.Close()
+
+	_, _ = s.Submit(ctx, "low1", WithAttributes(levelAttrs(2)))
+	_, _ = s.Submit(ctx, "low2", WithAttributes(levelAttrs(2)))
+	a, _ := s.Next(ctx)
+	b, _ := s.Next(ctx)
+
+	high, _ := s.Submit(ctx, "high", WithAttributes(levelAttrs(0)))
+	if !preempted(a.Preempted) || preempted(b.Preempted) {
+		t.Fatalf("expected only the oldest lease preempted")
+	}
+	// 触发抢占的任务被取消后，新的高优先级任务仍能抢占下一个 Lease。
+	_ = s.Cancel(ctx, high)
+	_, _ = s.Submit(ctx, "high2", WithAttributes(levelAttrs(0)))
+	if !preempted(b.Preempted) {
+		t.Fatalf("expected second lease preempted after trigger canceled")
+	}
+}
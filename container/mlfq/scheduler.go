@@ -2,8 +2,13 @@ package mlfq
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 	"sync"
 	"time"
+
+	"github.com/arknights-w/go-utils/container/mlfq/multiqueue"
+	"github.com/arknights-w/go-utils/container/pqueue"
 )
 
 // Option 用于配置调度器（如时钟注入、自动 Tick 等）。
@@ -13,6 +18,29 @@ type config struct {
 	now func() time.Time
 
 	autoTickInterval time.Duration
+	leaseTimeout     int
+	maxAgingPerTick  int
+	tenantQuota      int
+	tenantQuotas     map[string]int
+
+	capacity      int
+	levelCapacity int
+	admission     Admission
+	onEvict       any
+
+	codec   any
+	journal io.Writer
+
+	observer Observer
+
+	preempt       bool
+	preemptMinRun time.Duration
+
+	// tokenStride/tokenOffset 决定 token 空间：第 k 个任务的 token 为 k*stride+offset（分片调度器内部使用）。
+	tokenStride uint64
+	tokenOffset uint64
+	// onReady 在有任务变为可取时（持调度器锁）调用（分片调度器内部使用）。
+	onReady func()
 }
 
 // WithClock 注入时钟函数，便于测试可控。
@@ -22,7 +50,7 @@ func WithClock(now func() time.Time) Option {
 	}
 }
 
-// WithAutoTick 启用后台自动 Tick；interval<=0 表示禁用。
+// WithAutoTick 启用后台自动 Tick；interval<=0 表示禁用。每次 Tick 的时间取自 WithClock 提供的时钟。
 //
 // 开启后请在退出时调用 Close()，以停止后台 goroutine。
 func WithAutoTick(interval time.Duration) Option {
@@ -31,6 +59,27 @@ func WithAutoTick(interval time.Duration) Option {
 	}
 }
 
+// WithLeaseTimeout 启用 Lease 超时回收：Lease 发放后超过 multiple * Lease.Quantum 仍未 FeedBack，
+// 下一次 Tick 会回收该 Lease 并按“用满时间片”交给策略决定降级后重新入队；multiple<=0 表示禁用。
+//
+// 回收后 Token 不变，但每次 Lease 的 Epoch 不同：FeedBack 回传 Lease.Epoch（见 Lease.Feedback）时，
+// 被回收 Lease 的迟到 FeedBack（即使任务已被再次发放）返回 ErrLeaseExpired；
+// Epoch 为 0 时不校验，迟到的 FeedBack 只在任务尚未被再次发放时返回 ErrLeaseExpired。
+// 策略给出的 Quantum<=0 时该 Lease 不参与超时回收。
+func WithLeaseTimeout(multiple int) Option {
+	return func(c *config) {
+		c.leaseTimeout = multiple
+	}
+}
+
+// WithMaxAgingPerTick 限制单次 Tick 最多老化提升的任务数，避免积压过多时一次 Tick 长时间持锁阻塞 Submit；
+// n<=0 表示不限制。
+func WithMaxAgingPerTick(n int) Option {
+	return func(c *config) {
+		c.maxAgingPerTick = n
+	}
+}
+
 type scheduler[T any] struct {
 	mu     sync.Mutex
 	closed bool
@@ -38,9 +87,37 @@ type scheduler[T any] struct {
 	policy Policy[T]
 	mq     *MultiQueue[*taskState[T]]
 	states map[Token]*taskState[T]
+	// aging 是每个 level 按入队时间排序的索引，下标对应 level。
+	aging []agingList[T]
+	// tenants 是按租户的计数。
+	tenants map[string]*tenantState
+	// wait/turnaround 是每个 level 的排队等待时间（入队→Next）与周转时间（Submit→完成）分布。
+	wait       []histogram
+	turnaround []histogram
 
 	nextToken uint64
 
+	// waiters 是 NextWait 的等待队列（FIFO），可取的任务按登记顺序直接交给等待者。
+	waiters leaseWaitList[T]
+	// space 是 AdmitBlock 模式下等待空位的 Submit 队列。
+	space waitList
+	// leases 按 Lease 截止时间排序，仅在 WithLeaseTimeout 启用时使用。
+	leases *pqueue.Queue[*taskState[T], time.Time]
+	// running 是尚未被抢占的 Lease（见 runKey），preemptPending 是尚未被 Next 消化的抢占数；仅在 WithPreemption 启用时使用。
+	running        *pqueue.Queue[*taskState[T], runKey]
+	preemptPending int
+	// held 是等待前置任务完成的任务数。
+	held int
+	// delayed 按 notBefore 排序保存延迟中的任务（见 WithNotBefore）。
+	delayed *pqueue.Queue[*taskState[T], time.Time]
+	// onEvict 是 WithEvictCallback 设置的回调。
+	onEvict func(Token, T)
+	// onDrop 在任务未完成即被移除（取消/淘汰）时持锁调用，见 dropNotifier。
+	onDrop func(Token, error)
+	// codec 用于 Snapshot/Restore 与事件日志；jr 仅在 WithJournal 启用时非 nil。
+	codec Codec[T]
+	jr    *journal
+
 	cfg config
 
 	autoTickCancel context.CancelFunc
@@ -53,6 +130,12 @@ type scheduler[T any] struct {
 	promoted      uint64
 	demoted       uint64
 	agingPromoted uint64
+	moved         uint64
+	canceled      uint64
+	leaseExpired  uint64
+	preemptions   uint64
+	rejected      uint64
+	evicted       uint64
 }
 
 type taskState[T any] struct {
@@ -60,16 +143,44 @@ type taskState[T any] struct {
 	task       T
 	level      int
 	attrs      Attributes
+	tenant     string
+	ts         *tenantState
 	enqueuedAt time.Time
+	// qh 是任务在多级队列中的位置，用于 O(1) 移除。
+	qh multiqueue.Handle
+	// submittedAt 是 Submit 时间，用于统计周转时间。
+	submittedAt time.Time
 
 	// leased 表示当前已被 Next 发放 Lease，等待 FeedBack。
-	leased       bool
+	leased bool
+	// canceled 表示任务在 Lease 期间被 Cancel，下一次 FeedBack 时直接丢弃。
+	canceled bool
+	// expired 表示上一次 Lease 已超时被回收，用于让迟到的 FeedBack 返回 ErrLeaseExpired。
+	expired bool
+	// epoch 是当前（或最近一次）Lease 的序号，每次发放递增。
+	epoch       uint64
+	leaseHandle *pqueue.Handle[*taskState[T], time.Time]
+	// held 表示任务在等待前置任务完成（不在队列中），此时 level 为完成后要进入的 level；
+	// after 是声明的前置任务，waitingOn 是其中尚未完成的数量，dependents 是以本任务为前置任务的后继。
+	held       bool
+	after      []Token
+	waitingOn  int
+	dependents []*taskState[T]
+	// notBefore 是任务最早可入队的时间；delayHandle 非空表示任务在定时结构中（不在队列中）。
+	notBefore   time.Time
+	delayHandle *pqueue.Handle[*taskState[T], time.Time]
+	// preempt 是当前 Lease 的抢占通知，preempted 表示当前 Lease 已被抢占（仅在 WithPreemption 启用时使用）。
+	preempt      chan struct{}
+	preempted    bool
+	runHandle    *pqueue.Handle[*taskState[T], runKey]
+	agePrev      *taskState[T]
+	ageNext      *taskState[T]
 	lastDequeued time.Time
 	lastQuantum  time.Duration
 }
 
-// New 创建一个 MLFQ 调度器实例，并对外以接口 MLFQ[T] 暴露（隐藏内部实现细节）。
-func New[T any](policy Policy[T], opts ...Option) (MLFQ[T], error) {
+// New 创建一个 MLFQ 调度器实例，并对外以接口 Scheduler[T] 暴露（隐藏内部实现细节）。
+func New[T any](policy Policy[T], opts ...Option) (Scheduler[T], error) {
 	if policy == nil {
 		return nil, ErrNilPolicy
 	}
@@ -77,6 +188,7 @@ func New[T any](policy Policy[T], opts ...Option) (MLFQ[T], error) {
 	cfg := config{
 		now:              time.Now,
 		autoTickInterval: 0,
+		tokenStride:      1,
 	}
 	for _, o := range opts {
 		o(&cfg)
@@ -88,10 +200,43 @@ func New[T any](policy Policy[T], opts ...Option) (MLFQ[T], error) {
 	}
 
 	s := &scheduler[T]{
-		policy: policy,
-		mq:     NewMultiQueue[*taskState[T]](levels),
-		states: make(map[Token]*taskState[T]),
-		cfg:    cfg,
+		policy:  policy,
+		mq:      NewMultiQueue[*taskState[T]](levels),
+		states:  make(map[Token]*taskState[T]),
+		aging:   make([]agingList[T], levels),
+		tenants: make(map[string]*tenantState),
+		cfg:     cfg,
+
+		wait:       make([]histogram, levels),
+		turnaround: make([]histogram, levels),
+
+		delayed: pqueue.New[*taskState[T]](func(a, b time.Time) bool { return a.Before(b) }),
+	}
+	if cfg.codec != nil {
+		codec, ok := cfg.codec.(Codec[T])
+		if !ok {
+			return nil, ErrNoCodec
+		}
+		s.codec = codec
+	}
+	if cfg.onEvict != nil {
+		fn, ok := cfg.onEvict.(func(Token, T))
+		if !ok {
+			return nil, ErrEvictCallback
+		}
+		s.onEvict = fn
+	}
+	if cfg.journal != nil {
+		if s.codec == nil {
+			return nil, ErrNoCodec
+		}
+		s.jr = &journal{enc: json.NewEncoder(cfg.journal)}
+	}
+	if cfg.preempt {
+		s.running = pqueue.New[*taskState[T]](runBefore)
+	}
+	if cfg.leaseTimeout > 0 {
+		s.leases = pqueue.New[*taskState[T]](func(a, b time.Time) bool { return a.Before(b) })
 	}
 
 	if cfg.autoTickInterval > 0 {
@@ -102,16 +247,22 @@ func New[T any](policy Policy[T], opts ...Option) (MLFQ[T], error) {
 }
 
 // NewDefault 使用 DefaultPolicy 创建调度器。
-func NewDefault[T any](levels int, opts ...Option) (MLFQ[T], error) {
+func NewDefault[T any](levels int, opts ...Option) (Scheduler[T], error) {
 	return New[T](NewDefaultPolicy[T](levels, DefaultPolicyConfig{}), opts...)
 }
 
 // Close 关闭调度器并停止后台 auto-tick（若启用）。
 //
-// Close 幂等：允许重复调用。
+// Close 幂等：允许重复调用。启用 WithJournal 时返回首个日志写入错误。
 func (s *scheduler[T]) Close() error {
 	s.mu.Lock()
 	s.closed = true
+	var err error
+	if s.jr != nil {
+		err = s.jr.err
+	}
+	s.waiters.closeAll()
+	s.space.wakeAll()
 	cancel := s.autoTickCancel
 	s.autoTickCancel = nil
 	s.mu.Unlock()
@@ -120,7 +271,7 @@ func (s *scheduler[T]) Close() error {
 		cancel()
 	}
 	s.autoTickWG.Wait()
-	return nil
+	return err
 }
 
 func (s *scheduler[T]) startAutoTick(interval time.Duration) {
@@ -137,9 +288,9 @@ func (s *scheduler[T]) startAutoTick(interval time.Duration) {
 			select {
 			case <-ctx.Done():
 				return
-			case now := <-ticker.C:
-				// auto-tick 不依赖外部 ctx；Tick 内部会自行检查 closed。
-				s.Tick(context.Background(), now)
+			case <-ticker.C:
+				// auto-tick 不依赖外部 ctx；Tick 内部会自行检查 closed。时间取自 WithClock 提供的时钟。
+				s.Tick(context.Background(), s.cfg.now())
 			}
 		}
 	}()
@@ -167,19 +318,73 @@ func (s *scheduler[T]) Submit(ctx context.Context, task T, opts ...SubmitOption)
 		return 0, ErrInvalidLevel
 	}
 
-	s.nextToken++
-	tok := Token(s.nextToken)
+	notBefore := so.notBefore(now)
+
+	retry := false
+	for {
+		// 每次等待之后重新检查：前置任务可能在等待期间被取消，token 也可能已被其他 Submit 分配。
+		if err := s.checkAfterLocked(so.After); err != nil {
+			if retry {
+				s.wakeSpaceLocked()
+			}
+			return 0, err
+		}
+		if quota := s.cfg.quotaOf(so.Tenant); quota > 0 {
+			if ts := s.tenants[so.Tenant]; ts != nil && ts.pending() >= quota {
+				ts.rejected++
+				if retry {
+					// 放弃被唤醒时拿到的空位。
+					s.wakeSpaceLocked()
+				}
+				return 0, ErrTenantQuota
+			}
+		}
+		waited, err := s.admitLocked(ctx, level, retry)
+		if err != nil {
+			return 0, err
+		}
+		if !waited {
+			break
+		}
+		// 等待期间释放过锁：重新检查关闭状态、配额与容量。
+		if s.closed {
+			return 0, ErrClosed
+		}
+		now = s.cfg.now()
+		retry = true
+	}
+
+	tok := s.newTokenLocked()
+	ts := s.tenantLocked(so.Tenant)
 
 	st := &taskState[T]{
-		token:      tok,
-		task:       task,
-		level:      level,
-		attrs:      so.Attrs,
-		enqueuedAt: now,
+		token:  tok,
+		task:   task,
+		attrs:  so.Attrs,
+		tenant: so.Tenant,
+		ts:     ts,
+
+		submittedAt: now,
+		notBefore:   notBefore,
 	}
 	s.states[tok] = st
-	s.mq.Push(level, st)
+	ts.live++
+	ts.submitted++
 	s.submitted++
+	if s.holdLocked(st, so.After) {
+		st.level = level
+		s.logSubmit(st, now)
+		s.observe(EventSubmitted, st, level, now, false)
+		return tok, nil
+	}
+	if !s.readyLocked(st, level, now) {
+		s.logSubmit(st, now)
+		s.observe(EventSubmitted, st, level, now, false)
+		return tok, nil
+	}
+	s.logSubmit(st, now)
+	s.observe(EventSubmitted, st, level, now, false)
+	s.notifyLocked()
 	return tok, nil
 }
 
@@ -194,7 +399,94 @@ func (s *scheduler[T]) Next(ctx context.Context) (Lease[T], bool) {
 	if s.closed {
 		return zero, false
 	}
+	return s.nextLocked()
+}
 
+// NextWait 与 Next 相同，但队列为空时阻塞等待，直到有任务被提交/重新入队/老化提升，
+// 或 ctx 结束（返回 ctx.Err()）、调度器关闭（返回 ErrClosed）。
+//
+// 多个等待者严格按调用顺序（FIFO）获得任务：任务变为可取时直接交给最早的等待者，
+// 有等待者时后来的 NextWait 也不会插队。
+func (s *scheduler[T]) NextWait(ctx context.Context) (Lease[T], error) {
+	leases, err := s.NextWaitN(ctx, 1)
+	if err != nil {
+		var zero Lease[T]
+		return zero, err
+	}
+	return leases[0], nil
+}
+
+// NextWaitN 阻塞直到至少有一个任务可取，然后一次性取出至多 n 个 Lease（n<=0 按 1 处理）。
+func (s *scheduler[T]) NextWaitN(ctx context.Context, n int) ([]Lease[T], error) {
+	if n <= 0 {
+		n = 1
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s.closed {
+		return nil, ErrClosed
+	}
+
+	if s.waiters.Len() == 0 {
+		var out []Lease[T]
+		for len(out) < n {
+			lease, ok := s.nextLocked()
+			if !ok {
+				break
+			}
+			out = append(out, lease)
+		}
+		if len(out) > 0 {
+			return out, nil
+		}
+	}
+
+	w := s.waiters.add(n)
+	s.mu.Unlock()
+	select {
+	case <-w.done:
+	case <-ctx.Done():
+	}
+	s.mu.Lock()
+	if s.waiters.remove(w) {
+		return nil, ctx.Err()
+	}
+	// 已被唤醒：即使 ctx 同时结束，也返回已交付的 Lease，避免任务丢失。
+	if len(w.leases) == 0 {
+		return nil, ErrClosed
+	}
+	return w.leases, nil
+}
+
+// handoffLocked 按登记顺序把可取的任务直接交给 NextWait 等待者。
+func (s *scheduler[T]) handoffLocked() {
+	for s.mq.TotalLen() > 0 {
+		w, ok := s.waiters.front()
+		if !ok {
+			return
+		}
+		for len(w.leases) < w.n {
+			lease, ok := s.nextLocked()
+			if !ok {
+				break
+			}
+			w.leases = append(w.leases, lease)
+		}
+		if len(w.leases) == 0 {
+			// 策略暂不发放任务。
+			return
+		}
+		s.waiters.wakeFront()
+	}
+}
+
+// nextLocked 在持锁状态下按策略出队一个任务并发放 Lease。
+func (s *scheduler[T]) nextLocked() (Lease[T], bool) {
+	var zero Lease[T]
 	if s.mq.TotalLen() == 0 {
 		return zero, false
 	}
@@ -212,13 +504,24 @@ func (s *scheduler[T]) Next(ctx context.Context) (Lease[T], bool) {
 	if !ok || st == nil {
 		return zero, false
 	}
+	s.aging[level].remove(st)
+	st.ts.queued--
+	s.wakeSpaceLocked()
+	s.wait[level].record(now.Sub(st.enqueuedAt))
 
 	q := s.policy.Quantum(now, level, st.task)
 	st.level = level
 	st.leased = true
+	st.ts.leased++
+	st.expired = false
+	st.epoch++
 	st.lastDequeued = now
 	st.lastQuantum = q
+	if s.leases != nil && q > 0 {
+		st.leaseHandle = s.leases.Push(st, now.Add(q*time.Duration(s.cfg.leaseTimeout)))
+	}
 	s.dequeued++
+	s.observe(EventDequeued, st, level, now, false)
 
 	return Lease[T]{
 		Token:      st.token,
@@ -226,6 +529,8 @@ func (s *scheduler[T]) Next(ctx context.Context) (Lease[T], bool) {
 		Level:      level,
 		Quantum:    q,
 		DequeuedAt: now,
+		Epoch:      st.epoch,
+		Preempted:  s.leaseStartLocked(st),
 	}, true
 }
 
@@ -244,7 +549,14 @@ func (s *scheduler[T]) FeedBack(ctx context.Context, token Token, fb Feedback) e
 	if !ok {
 		return ErrUnknownToken
 	}
+	if s.leases != nil && fb.Epoch != 0 && fb.Epoch != st.epoch {
+		// 旧 Lease 已被回收（任务可能已被再次发放）。
+		return ErrLeaseExpired
+	}
 	if !st.leased {
+		if st.expired {
+			return ErrLeaseExpired
+		}
 		return ErrNotLeased
 	}
 
@@ -253,24 +565,37 @@ func (s *scheduler[T]) FeedBack(ctx context.Context, token Token, fb Feedback) e
 	if !fb.UsedFullQuantum && st.lastQuantum > 0 && fb.RanFor >= st.lastQuantum {
 		fb.UsedFullQuantum = true
 	}
-	// 默认使用提交时的 attrs；若调用方希望动态调整，可在 Feedback.Attrs 中提供新值。
-	if fb.Attrs == (Attributes{}) {
-		fb.Attrs = st.attrs
-	}
+	// Feedback.Attrs 中的零值字段沿用任务当前的属性，只有非零字段被更新。
+	fb.Attrs = st.attrs.merge(fb.Attrs)
 
 	st.leased = false
+	st.ts.leased--
+	s.releaseLease(st)
+	if s.leaseEndLocked(st) {
+		fb.Preempted = true
+	}
 
-	if fb.Finished {
-		delete(s.states, token)
-		s.finished++
+	if st.canceled {
+		s.dropLocked(st, context.Canceled)
 		return nil
 	}
 
 	oldLevel := st.level
-	newLevel, requeue := s.policy.OnFeedback(now, oldLevel, st.task, fb)
+	var (
+		newLevel int
+		requeue  bool
+	)
+	if fb.Finished {
+		if f, ok := s.policy.(Finisher[T]); ok {
+			f.OnFinish(now, oldLevel, st.task, fb)
+		}
+	} else {
+		newLevel, requeue = s.policy.OnFeedback(now, oldLevel, st.task, fb)
+	}
 	if !requeue {
-		delete(s.states, token)
-		s.finished++
+		s.finishLocked(st, now)
+		s.logFeedback(st, now, true)
+		s.observe(EventFinished, st, oldLevel, now, fb.UsedFullQuantum)
 		return nil
 	}
 	if newLevel < 0 || newLevel >= s.mq.Levels() {
@@ -284,14 +609,72 @@ func (s *scheduler[T]) FeedBack(ctx context.Context, token Token, fb Feedback) e
 		s.demoted++
 	}
 
-	st.level = newLevel
 	st.attrs = fb.Attrs
-	st.enqueuedAt = now
-	s.mq.Push(newLevel, st)
+	s.enqueueLocked(st, newLevel, now)
 	s.requeued++
+	s.logFeedback(st, now, false)
+	s.observe(EventFeedback, st, oldLevel, now, fb.UsedFullQuantum)
+	s.notifyLocked()
 	return nil
 }
 
+// Cancel 取消任务。
+//
+// 排队中的任务立即从所在 level 移除；已发放 Lease 的任务仅做标记，
+// 其下一次 FeedBack 会直接丢弃该任务（不再调用策略、不再入队）。
+// 对同一个已标记的 Lease 任务重复 Cancel 返回 nil。
+func (s *scheduler[T]) Cancel(ctx context.Context, token Token) error {
+	_, err := s.cancel(ctx, token)
+	return err
+}
+
+// cancel 实现 Cancel，并报告任务是否处于 Lease 中（此时取消在其 FeedBack 时生效）。
+func (s *scheduler[T]) cancel(ctx context.Context, token Token) (leased bool, err error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false, ErrClosed
+	}
+
+	st, ok := s.states[token]
+	if !ok {
+		return false, ErrUnknownToken
+	}
+	if st.leased {
+		if !st.canceled {
+			st.canceled = true
+			s.canceled++
+			st.ts.canceled++
+			// 日志中直接记为移除：重放时任务总是排队中，其后的 FeedBack 事件会因 token 不存在而被跳过。
+			s.logRemove(journalCancel, st)
+			s.observe(EventCanceled, st, st.level, s.cfg.now(), false)
+			s.cancelDependentsLocked(st)
+		}
+		return true, nil
+	}
+
+	if !st.held && st.delayHandle == nil && !s.unqueueLocked(st) {
+		return false, ErrUnknownToken
+	}
+	s.dropLocked(st, context.Canceled)
+	s.canceled++
+	st.ts.canceled++
+	s.logRemove(journalCancel, st)
+	s.observe(EventCanceled, st, st.level, s.cfg.now(), false)
+	return false, nil
+}
+
+// notifyDrop 设置任务未完成即被移除时的回调（见 dropNotifier）。
+func (s *scheduler[T]) notifyDrop(fn func(Token, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onDrop = fn
+}
+
 func (s *scheduler[T]) Tick(ctx context.Context, now time.Time) {
 	if ctx.Err() != nil {
 		return
@@ -303,47 +686,231 @@ func (s *scheduler[T]) Tick(ctx context.Context, now time.Time) {
 		return
 	}
 
+	if s.jr != nil {
+		s.jr.tick = &journalEvent{Op: journalTick, At: now}
+	}
+	s.reclaimExpiredLeases(now)
+	s.releaseDelayedLocked(now)
+	s.agingLocked(now)
+	// 运行时间此前不足 minRun 的 Lease 可能已经可以被抢占。
+	s.preemptLocked(now)
+	if s.jr != nil {
+		if ev := s.jr.tick; len(ev.Moves) > 0 || len(ev.Drops) > 0 {
+			s.jr.write(ev)
+		}
+		s.jr.tick = nil
+	}
+}
+
+// agingLocked 对每个 level（由低优先级到高优先级）从等待最久的任务开始逐个询问策略是否老化提升，
+// 直到遇到第一个不需要提升的任务，或达到 WithMaxAgingPerTick 的上限。
+//
+// 这里假设 OnAging 对等待时长单调：等待更久的任务不会比等待更短的任务“更不该”被提升。
+func (s *scheduler[T]) agingLocked(now time.Time) {
+	budget := s.cfg.maxAgingPerTick
+	promoted := 0
 	levels := s.mq.Levels()
 	for level := levels - 1; level >= 1; level-- {
-		st, ok := s.mq.Peek(level)
-		if !ok || st == nil {
-			continue
+		for st := s.aging[level].head; st != nil; {
+			if budget > 0 && promoted >= budget {
+				return
+			}
+			next := st.ageNext
+			promote, newLevel := s.policy.OnAging(now, level, st.task, st.enqueuedAt)
+			if !promote {
+				break
+			}
+			if newLevel >= 0 && newLevel < levels && newLevel != level {
+				s.unqueueLocked(st)
+				s.enqueueLocked(st, newLevel, now)
+				s.logTickMove(st)
+				s.observe(EventAgingPromoted, st, level, now, false)
+				s.agingPromoted++
+				promoted++
+				s.notifyLocked()
+			}
+			st = next
 		}
-		promote, newLevel := s.policy.OnAging(now, level, st.task, st.enqueuedAt)
-		if !promote {
-			continue
+	}
+}
+
+// releaseLease 撤销 Lease 的超时跟踪。
+func (s *scheduler[T]) releaseLease(st *taskState[T]) {
+	if st.leaseHandle != nil {
+		s.leases.Remove(st.leaseHandle)
+		st.leaseHandle = nil
+	}
+}
+
+// reclaimExpiredLeases 回收截止时间 <= now 的 Lease：视为“用满时间片”交给策略决定新 level 并重新入队。
+func (s *scheduler[T]) reclaimExpiredLeases(now time.Time) {
+	if s.leases == nil {
+		return
+	}
+	for {
+		st, deadline, ok := s.leases.Peek()
+		if !ok || deadline.After(now) {
+			return
 		}
-		if newLevel < 0 || newLevel >= levels {
+		s.leases.Pop()
+		st.leaseHandle = nil
+		st.leased = false
+		st.ts.leased--
+		preempted := s.leaseEndLocked(st)
+		st.expired = true
+		s.leaseExpired++
+
+		if st.canceled {
+			s.dropLocked(st, context.Canceled)
 			continue
 		}
-		if newLevel == level {
+
+		oldLevel := st.level
+		newLevel, requeue := s.policy.OnFeedback(now, oldLevel, st.task, Feedback{
+			RanFor:          now.Sub(st.lastDequeued),
+			UsedFullQuantum: true,
+			Preempted:       preempted,
+			Attrs:           st.attrs,
+		})
+		if !requeue {
+			s.finishLocked(st, now)
+			s.logTickDrop(st)
+			s.observe(EventFinished, st, oldLevel, now, true)
 			continue
 		}
+		if newLevel < 0 || newLevel >= s.mq.Levels() {
+			newLevel = oldLevel
+		}
+		if newLevel > oldLevel {
+			s.demoted++
+		}
+		s.enqueueLocked(st, newLevel, now)
+		s.logTickMove(st)
+		s.observe(EventLeaseExpired, st, oldLevel, now, true)
+		s.requeued++
+		s.notifyLocked()
+	}
+}
 
-		_, _ = s.mq.Pop(level)
-		st.level = newLevel
-		st.enqueuedAt = now
-		s.mq.Push(newLevel, st)
-		s.agingPromoted++
+// newTokenLocked 分配下一个 token。
+func (s *scheduler[T]) newTokenLocked() Token {
+	s.nextToken++
+	return Token(s.nextToken*s.cfg.tokenStride + s.cfg.tokenOffset)
+}
+
+// seeTokenLocked 确保之后分配的 token 不与 tok（来自快照/日志）冲突。
+func (s *scheduler[T]) seeTokenLocked(tok Token) {
+	s.nextToken = max(s.nextToken, (uint64(tok)-s.cfg.tokenOffset)/s.cfg.tokenStride)
+}
+
+// notifyLocked 把任务交给 NextWait 等待者并检查抢占（有任务变为可取时调用）。
+func (s *scheduler[T]) notifyLocked() {
+	s.handoffLocked()
+	s.preemptLocked(s.cfg.now())
+	if s.cfg.onReady != nil {
+		s.cfg.onReady()
 	}
 }
 
+// notifyAllLocked 在批量恢复任务后把任务交给 NextWait 等待者。
+func (s *scheduler[T]) notifyAllLocked() {
+	s.handoffLocked()
+	if s.cfg.onReady != nil {
+		s.cfg.onReady()
+	}
+}
+
+// enqueueLocked 将任务以 enqueuedAt=now 放入 level 队尾，并登记到老化索引。
+func (s *scheduler[T]) enqueueLocked(st *taskState[T], level int, now time.Time) {
+	st.level = level
+	st.enqueuedAt = now
+	st.qh = s.mq.PushKey(level, st.tenant, st)
+	s.aging[level].insert(st)
+	st.ts.queued++
+}
+
+// unqueueLocked 将排队中的任务从其所在 level 移除（不修改 states）。
+func (s *scheduler[T]) unqueueLocked(st *taskState[T]) bool {
+	if _, ok := s.mq.Remove(st.qh); !ok {
+		return false
+	}
+	s.aging[st.level].remove(st)
+	st.ts.queued--
+	s.wakeSpaceLocked()
+	s.clampPreemptLocked()
+	return true
+}
+
+// dropLocked 移除被取消（reason 为 context.Canceled）或淘汰（ErrEvicted）的任务（任务此时必须不在队列中），
+// 并级联取消等待它的后继。
+func (s *scheduler[T]) dropLocked(st *taskState[T], reason error) {
+	s.removeLocked(st)
+	if s.onDrop != nil {
+		s.onDrop(st.token, reason)
+	}
+	s.cancelDependentsLocked(st)
+}
+
+// removeLocked 将任务从 states 中移除，并释放其等待/延迟状态。
+func (s *scheduler[T]) removeLocked(st *taskState[T]) {
+	delete(s.states, st.token)
+	st.ts.live--
+	if st.held {
+		st.held = false
+		s.held--
+		s.wakeSpaceLocked()
+	}
+	if st.delayHandle != nil {
+		s.delayed.Remove(st.delayHandle)
+		st.delayHandle = nil
+		s.wakeSpaceLocked()
+	}
+}
+
+// finishLocked 移除已完成的任务并计入完成数与周转时间（按完成时所在 level 统计），然后释放其后继。
+func (s *scheduler[T]) finishLocked(st *taskState[T], now time.Time) {
+	s.releaseDependentsLocked(st, now)
+	s.removeLocked(st)
+	s.finished++
+	st.ts.finished++
+	s.turnaround[st.level].record(now.Sub(st.submittedAt))
+}
+
 func (s *scheduler[T]) Stats(ctx context.Context) Stats {
 	_ = ctx
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.statsLocked()
+}
 
-	by := make([]int, s.mq.Levels())
+func (s *scheduler[T]) statsLocked() Stats {
+	now := s.cfg.now()
+	levels := s.mq.Levels()
+	by := make([]int, levels)
+	wait := make([]LatencyStats, levels)
+	turnaround := make([]LatencyStats, levels)
+	headWait := make([]time.Duration, levels)
 	for i := range by {
 		by[i] = s.mq.Len(i)
+		wait[i] = s.wait[i].stats()
+		turnaround[i] = s.turnaround[i].stats()
+		if head := s.aging[i].head; head != nil {
+			headWait[i] = now.Sub(head.enqueuedAt)
+		}
 	}
 
 	return Stats{
-		Now:      s.cfg.now(),
-		Levels:   s.mq.Levels(),
+		Now:      now,
+		Levels:   levels,
 		TotalLen: s.mq.TotalLen(),
+		Held:     s.held,
+		Delayed:  s.delayed.Len(),
 		ByLevel:  by,
 
+		Wait:       wait,
+		Turnaround: turnaround,
+		HeadWait:   headWait,
+
 		Submitted:     s.submitted,
 		Dequeued:      s.dequeued,
 		Finished:      s.finished,
@@ -351,6 +918,14 @@ func (s *scheduler[T]) Stats(ctx context.Context) Stats {
 		Promoted:      s.promoted,
 		Demoted:       s.demoted,
 		AgingPromoted: s.agingPromoted,
+		Moved:         s.moved,
+		Canceled:      s.canceled,
+		LeaseExpired:  s.leaseExpired,
+		Preempted:     s.preemptions,
+		Rejected:      s.rejected,
+		Evicted:       s.evicted,
+
+		Tenants: s.tenantStatsLocked(),
 
 		BitMapWords: s.mq.BitMapWords(),
 	}
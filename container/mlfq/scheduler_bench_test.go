@@ -2,6 +2,7 @@ package mlfq
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 )
@@ -92,3 +93,58 @@ func BenchmarkScheduler_Parallel_NextFeedback(b *testing.B) {
 		}
 	})
 }
+
+func BenchmarkScheduler_Tick_AgingBacklog(b *testing.B) {
+	ctx := context.Background()
+	clock := time.Unix(100, 0)
+	s, _ := New(
+		NewDefaultPolicy[int](8, DefaultPolicyConfig{AgingThreshold: 1 * time.Second}),
+		WithClock(func() time.Time { return clock }),
+		WithMaxAgingPerTick(1024),
+	)
+	defer s.Close()
+
+	// 积压在最低 level 的任务，每次 Tick 最多提升 1024 个。
+	for i := range 1 << 16 {
+		_, _ = s.Submit(ctx, i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		clock = clock.Add(2 * time.Second)
+		s.Tick(ctx, clock)
+	}
+}
+
+// BenchmarkSharded_Parallel_NextFeedback 与 BenchmarkScheduler_Parallel_NextFeedback 同样的负载，对比不同分片数。
+func BenchmarkSharded_Parallel_NextFeedback(b *testing.B) {
+	ctx := context.Background()
+	for _, shards := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			s, _ := NewSharded(shards, func() Policy[int] {
+				return NewDefaultPolicy[int](64, DefaultPolicyConfig{})
+			})
+			defer s.Close()
+
+			const preload = 1 << 14
+			for i := range preload {
+				_, _ = s.Submit(ctx, i)
+			}
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					lease, ok := s.Next(ctx)
+					if !ok {
+						continue
+					}
+					_ = s.FeedBack(ctx, lease.Token, Feedback{
+						RanFor:          lease.Quantum,
+						Finished:        false,
+						UsedFullQuantum: true,
+					})
+				}
+			})
+		})
+	}
+}
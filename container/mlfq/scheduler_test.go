@@ -2,6 +2,7 @@ package mlfq
 
 import (
 	"context"
+	"runtime"
 	"testing"
 	"time"
 )
@@ -39,7 +40,7 @@ func TestScheduler_FeedBack_NotLeased(t *testing.T) {
 	}
 }
 
-func TestScheduler_TickAgingPromotesHead(t *testing.T) {
+func TestScheduler_TickAgingPromotesAllEligible(t *testing.T) {
 	now := time.Unix(10, 0)
 	clock := now
 	s, _ := New[string](NewDefaultPolicy[string](3, DefaultPolicyConfig{
@@ -53,16 +54,289 @@ func TestScheduler_TickAgingPromotesHead(t *testing.T) {
 	// low priority tasks (level 2) enqueued at t=10
 	_, _ = s.Submit(ctx, "a", WithAttributes(Attributes{Urgency: 0, Importance: 0}))
 	_, _ = s.Submit(ctx, "b", WithAttributes(Attributes{Urgency: 0, Importance: 0}))
+	// enqueued at t=18: not yet past threshold at t=20
+	clock = time.Unix(18, 0)
+	_, _ = s.Submit(ctx, "c", WithAttributes(Attributes{Urgency: 0, Importance: 0}))
 
-	// Fast-forward: now=20, waited 10s >= threshold => promote from level 2 to 1
+	// Fast-forward: now=20, a/b waited 10s >= threshold => both promoted from level 2 to 1
 	clock = time.Unix(20, 0)
 	s.Tick(ctx, clock)
 
 	st := s.Stats(ctx)
-	if st.AgingPromoted == 0 {
-		t.Fatalf("expected aging promoted > 0")
+	if st.AgingPromoted != 2 {
+		t.Fatalf("expected aging promoted == 2 got %d", st.AgingPromoted)
 	}
-	if st.ByLevel[1] != 1 || st.ByLevel[2] != 1 {
-		t.Fatalf("expected one promoted into level 1 and one remaining in level 2, got %+v", st.ByLevel)
+	if st.ByLevel[1] != 2 || st.ByLevel[2] != 1 {
+		t.Fatalf("expected two promoted into level 1 and one remaining in level 2, got %+v", st.ByLevel)
 	}
 }
+
+func TestScheduler_TickAgingMaxPerTick(t *testing.T) {
+	clock := time.Unix(10, 0)
+	s, _ := New[int](NewDefaultPolicy[int](3, DefaultPolicyConfig{AgingThreshold: 5 * time.Second}),
+		WithClock(func() time.Time { return clock }), WithMaxAgingPerTick(3))
+	defer s.Close()
+
+	ctx := context.Background()
+	for i := range 10 {
+		_, _ = s.Submit(ctx, i)
+	}
+	clock = time.Unix(20, 0)
+	s.Tick(ctx, clock)
+	if st := s.Stats(ctx); st.AgingPromoted != 3 || st.ByLevel[1] != 3 {
+		t.Fatalf("expected 3 promoted got %+v", st)
+	}
+	s.Tick(ctx, clock)
+	s.Tick(ctx, clock)
+	s.Tick(ctx, clock)
+	if st := s.Stats(ctx); st.AgingPromoted != 10 || st.ByLevel[2] != 0 {
+		t.Fatalf("expected all promoted got %+v", st)
+	}
+
+	// 提升后的任务按原顺序出队
+	for i := range 10 {
+		lease, _ := s.Next(ctx)
+		if lease.Task != i {
+			t.Fatalf("expected %d got %d", i, lease.Task)
+		}
+	}
+}
+
+func TestScheduler_CancelQueued(t *testing.T) {
+	ctx := context.Background()
+	s, _ := NewDefault[string](4)
+	defer s.Close()
+	a, _ := s.Submit(ctx, "a")
+	_, _ = s.Submit(ctx, "b")
+
+	if err := s.Cancel(ctx, a); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if err := s.Cancel(ctx, a); err != ErrUnknownToken {
+		t.Fatalf("expected ErrUnknownToken got %v", err)
+	}
+	lease, ok := s.Next(ctx)
+	if !ok || lease.Task != "b" {
+		t.Fatalf("expected b got %q ok=%v", lease.Task, ok)
+	}
+	st := s.Stats(ctx)
+	if st.Canceled != 1 || st.TotalLen != 0 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+}
+
+func TestScheduler_CancelLeased(t *testing.T) {
+	ctx := context.Background()
+	s, _ := NewDefault[string](4)
+	defer s.Close()
+	tok, _ := s.Submit(ctx, "a")
+	lease, _ := s.Next(ctx)
+
+	if err := s.Cancel(ctx, tok); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	// 被取消的 Lease 在 FeedBack 时被丢弃，即使未完成也不会重新入队。
+	if err := s.FeedBack(ctx, lease.Token, Feedback{RanFor: lease.Quantum}); err != nil {
+		t.Fatalf("feedback: %v", err)
+	}
+	if _, ok := s.Next(ctx); ok {
+		t.Fatalf("expected empty")
+	}
+	if err := s.FeedBack(ctx, lease.Token, Feedback{}); err != ErrUnknownToken {
+		t.Fatalf("expected ErrUnknownToken got %v", err)
+	}
+	if st := s.Stats(ctx); st.Canceled != 1 || st.Requeued != 0 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+}
+
+func TestScheduler_NextWaitWakesOnSubmit(t *testing.T) {
+	ctx := context.Background()
+	s, _ := NewDefault[string](4)
+	defer s.Close()
+
+	got := make(chan string, 1)
+	go func() {
+		lease, err := s.NextWait(ctx)
+		if err != nil {
+			got <- err.Error()
+			return
+		}
+		got <- lease.Task
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	_, _ = s.Submit(ctx, "a")
+	select {
+	case v := <-got:
+		if v != "a" {
+			t.Fatalf("expected a got %q", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("NextWait not woken")
+	}
+}
+
+func TestScheduler_NextWaitCtxAndClose(t *testing.T) {
+	s, _ := NewDefault[string](4)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.NextWait(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded got %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.NextWait(context.Background())
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	_ = s.Close()
+	if err := <-done; err != ErrClosed {
+		t.Fatalf("expected ErrClosed got %v", err)
+	}
+}
+
+func TestScheduler_NextWaitN_Batch(t *testing.T) {
+	ctx := context.Background()
+	s, _ := NewDefault[int](4)
+	defer s.Close()
+	for i := range 5 {
+		_, _ = s.Submit(ctx, i)
+	}
+	leases, err := s.NextWaitN(ctx, 3)
+	if err != nil || len(leases) != 3 {
+		t.Fatalf("expected 3 leases got %d err=%v", len(leases), err)
+	}
+	leases, err = s.NextWaitN(ctx, 3)
+	if err != nil || len(leases) != 2 {
+		t.Fatalf("expected 2 leases got %d err=%v", len(leases), err)
+	}
+}
+
+func TestScheduler_NextWaitFIFOWaiters(t *testing.T) {
+	ctx := context.Background()
+	s, _ := NewDefault[int](4)
+	defer s.Close()
+	sch := s.(*scheduler[int])
+
+	const waiters = 3
+	got := make([]chan int, waiters)
+	for i := range waiters {
+		got[i] = make(chan int, 1)
+		go func() {
+			lease, err := s.NextWait(ctx)
+			if err != nil {
+				close(got[i])
+				return
+			}
+			got[i] <- lease.Task
+		}()
+		// 等第 i 个等待者登记后再启动下一个，保证登记顺序
+		waitForWaiters(t, sch, i+1)
+	}
+
+	for i := range waiters {
+		_, _ = s.Submit(ctx, i)
+		// 任务已直接交给等待者：后来的调用方取不到
+		if _, ok := s.Next(ctx); ok {
+			t.Fatalf("task %d taken ahead of waiter", i)
+		}
+	}
+	for i := range waiters {
+		if task := <-got[i]; task != i {
+			t.Fatalf("expected waiter %d to get task %d got %d", i, i, task)
+		}
+	}
+}
+
+// waitForWaiters 等待调度器中登记的 NextWait 等待者达到 n 个。
+func waitForWaiters(t *testing.T, s *scheduler[int], n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s.mu.Lock()
+		l := s.waiters.Len()
+		s.mu.Unlock()
+		if l >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d waiters got %d", n, l)
+		}
+		runtime.Gosched()
+	}
+}
+
+func TestScheduler_LeaseTimeoutReclaims(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Unix(0, 0)
+	s, _ := New[string](NewDefaultPolicy[string](4, DefaultPolicyConfig{BaseQuantum: 10 * time.Millisecond}),
+		WithClock(func() time.Time { return clock }), WithLeaseTimeout(2))
+	defer s.Close()
+
+	_, _ = s.Submit(ctx, "a", WithAttributes(Attributes{Urgency: 100, Importance: 100}))
+	lease, _ := s.Next(ctx)
+	if lease.Level != 0 {
+		t.Fatalf("expected level 0 got %d", lease.Level)
+	}
+
+	// 未超过 2*Quantum：不回收
+	clock = clock.Add(lease.Quantum)
+	s.Tick(ctx, clock)
+	if st := s.Stats(ctx); st.LeaseExpired != 0 || st.TotalLen != 0 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+
+	clock = clock.Add(lease.Quantum)
+	s.Tick(ctx, clock)
+	st := s.Stats(ctx)
+	if st.LeaseExpired != 1 || st.ByLevel[1] != 1 {
+		t.Fatalf("expected reclaimed and demoted to level 1, got %+v", st)
+	}
+	if err := s.FeedBack(ctx, lease.Token, Feedback{Epoch: lease.Epoch, Finished: true}); err != ErrLeaseExpired {
+		t.Fatalf("expected ErrLeaseExpired got %v", err)
+	}
+
+	again, ok := s.Next(ctx)
+	if !ok || again.Token != lease.Token || again.Level != 1 || again.Epoch == lease.Epoch {
+		t.Fatalf("expected re-lease at level 1 with new epoch got %+v ok=%v", again, ok)
+	}
+	// 旧消费者在任务被再次发放后才 FeedBack：仍视为过期，不影响新 Lease
+	if err := s.FeedBack(ctx, lease.Token, Feedback{Epoch: lease.Epoch, Finished: true}); err != ErrLeaseExpired {
+		t.Fatalf("expected ErrLeaseExpired for stale lease got %v", err)
+	}
+	if st := s.Stats(ctx); st.Finished != 0 {
+		t.Fatalf("stale feedback finished the task: %+v", st)
+	}
+	// 不回传 Epoch 的调用方不受影响。
+	if err := s.FeedBack(ctx, again.Token, Feedback{Finished: true}); err != nil {
+		t.Fatalf("feedback without epoch: %v", err)
+	}
+}
+
+func TestScheduler_LeaseTimeoutZeroQuantum(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Unix(0, 0)
+	s, _ := New[string](zeroQuantumPolicy{NewDefaultPolicy[string](2, DefaultPolicyConfig{})},
+		WithClock(func() time.Time { return clock }), WithLeaseTimeout(2))
+	defer s.Close()
+
+	_, _ = s.Submit(ctx, "a")
+	lease, _ := s.Next(ctx)
+	clock = clock.Add(time.Second)
+	s.Tick(ctx, clock)
+	if st := s.Stats(ctx); st.LeaseExpired != 0 {
+		t.Fatalf("zero quantum lease reclaimed: %+v", st)
+	}
+	if err := s.FeedBack(ctx, lease.Token, lease.Feedback(Feedback{Finished: true})); err != nil {
+		t.Fatalf("feedback: %v", err)
+	}
+}
+
+type zeroQuantumPolicy struct {
+	*DefaultPolicy[string]
+}
+
+func (zeroQuantumPolicy) Quantum(time.Time, int, string) time.Duration { return 0 }
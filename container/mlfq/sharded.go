@@ -0,0 +1,427 @@
+package mlfq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// sharded 是由多个独立 scheduler 组成的分片调度器，用于降低多消费者下单把锁的竞争。
+//
+// token 按 k*shards+i 编码所属分片，FeedBack/Cancel 可直接路由；Next 从随机选出的分片开始，
+// 本分片为空时依次窃取其它分片的任务。
+type sharded[T any] struct {
+	shards []*scheduler[T]
+
+	closed atomic.Bool
+
+	// ready 在任一分片有任务变为可取时被关闭并重置，用于唤醒 NextWait；
+	// waiting 是正在等待的 NextWait 数，为 0 时 notify 不加锁直接返回。
+	mu      sync.Mutex
+	ready   chan struct{}
+	waiting atomic.Int64
+}
+
+// NewSharded 创建由 shards 个独立调度器组成的分片调度器（shards<=0 时按 1 处理），并以 Scheduler[T] 暴露。
+//
+// newPolicy 为每个分片创建一个策略实例（策略可能有内部状态，不能共享）；opts 作用于每个分片。
+// 与单个调度器相比的差异：
+//   - 优先级只在分片内严格保证：Next 取到的是某个非空分片的最高优先级任务，而非全局最高
+//   - 指定租户的任务固定路由到同一分片（租户配额仍然有效），默认租户的任务随机分布
+//   - 带 WithAfter 的任务路由到前置任务所在分片，前置任务跨分片时返回 ErrCrossShard
+//   - WithCapacity/WithLevelCapacity 按分片生效
+//   - WithObserver 可能被不同分片并发调用
+//   - WithPreemption 只在分片内抢占
+func NewSharded[T any](shards int, newPolicy func() Policy[T], opts ...Option) (Scheduler[T], error) {
+	if newPolicy == nil {
+		return nil, ErrNilPolicy
+	}
+	if shards <= 0 {
+		shards = 1
+	}
+
+	var probe config
+	for _, o := range opts {
+		o(&probe)
+	}
+	base := slices.Clip(opts)
+	if probe.journal != nil {
+		// 各分片持有各自的锁，共享同一个日志 writer 时需要串行化。
+		base = append(base, WithJournal(&lockedWriter{w: probe.journal}))
+	}
+
+	s := &sharded[T]{shards: make([]*scheduler[T], 0, shards)}
+	for i := range shards {
+		q, err := New(newPolicy(), append(slices.Clip(base), func(c *config) {
+			c.tokenStride = uint64(shards)
+			c.tokenOffset = uint64(i)
+			c.onReady = s.notify
+		})...)
+		if err != nil {
+			_ = s.Close()
+			return nil, err
+		}
+		s.shards = append(s.shards, q.(*scheduler[T]))
+	}
+	return s, nil
+}
+
+func (s *sharded[T]) shardOf(token Token) *scheduler[T] {
+	return s.shards[uint64(token)%uint64(len(s.shards))]
+}
+
+// notify 唤醒所有 NextWait 等待者（由分片在持有分片锁时调用）。
+func (s *sharded[T]) notify() {
+	if s.waiting.Load() == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ready != nil {
+		close(s.ready)
+		s.ready = nil
+	}
+}
+
+func (s *sharded[T]) readyChan() chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ready == nil {
+		s.ready = make(chan struct{})
+	}
+	return s.ready
+}
+
+func (s *sharded[T]) Submit(ctx context.Context, task T, opts ...SubmitOption) (Token, error) {
+	var so SubmitOptions
+	for _, o := range opts {
+		o(&so)
+	}
+	var i uint64
+	if len(so.After) > 0 {
+		// 依赖只在分片内跟踪：任务与其前置任务必须位于同一分片。
+		sh := s.shardOf(so.After[0])
+		for _, t := range so.After[1:] {
+			if s.shardOf(t) != sh {
+				return 0, ErrCrossShard
+			}
+		}
+		return sh.Submit(ctx, task, opts...)
+	}
+	if so.Tenant != "" {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(so.Tenant))
+		i = h.Sum64()
+	} else {
+		// 随机选择分片：避免多个提交者争用同一个计数器。
+		i = rand.Uint64()
+	}
+	return s.shards[i%uint64(len(s.shards))].Submit(ctx, task, opts...)
+}
+
+func (s *sharded[T]) Next(ctx context.Context) (Lease[T], bool) {
+	n := uint64(len(s.shards))
+	start := rand.Uint64()
+	for i := range n {
+		if lease, ok := s.shards[(start+i)%n].Next(ctx); ok {
+			return lease, true
+		}
+	}
+	var zero Lease[T]
+	return zero, false
+}
+
+// NextWait 与 Next 相同，但所有分片都为空时阻塞等待。
+//
+// 与单个调度器不同，等待者之间不保证 FIFO：有任务可取时所有等待者被同时唤醒并竞争。
+func (s *sharded[T]) NextWait(ctx context.Context) (Lease[T], error) {
+	leases, err := s.NextWaitN(ctx, 1)
+	if err != nil {
+		var zero Lease[T]
+		return zero, err
+	}
+	return leases[0], nil
+}
+
+func (s *sharded[T]) NextWaitN(ctx context.Context, n int) ([]Lease[T], error) {
+	if n <= 0 {
+		n = 1
+	}
+	s.waiting.Add(1)
+	defer s.waiting.Add(-1)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if s.closed.Load() {
+			return nil, ErrClosed
+		}
+
+		// 先登记（waiting 与 ready）再取：取空后到等待前之间到达的任务也会关闭这个 channel。
+		ready := s.readyChan()
+		var out []Lease[T]
+		for len(out) < n {
+			lease, ok := s.Next(ctx)
+			if !ok {
+				break
+			}
+			out = append(out, lease)
+		}
+		if len(out) > 0 {
+			return out, nil
+		}
+
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (s *sharded[T]) FeedBack(ctx context.Context, token Token, fb Feedback) error {
+	return s.shardOf(token).FeedBack(ctx, token, fb)
+}
+
+func (s *sharded[T]) Cancel(ctx context.Context, token Token) error {
+	return s.shardOf(token).Cancel(ctx, token)
+}
+
+func (s *sharded[T]) cancel(ctx context.Context, token Token) (bool, error) {
+	return s.shardOf(token).cancel(ctx, token)
+}
+
+func (s *sharded[T]) notifyDrop(fn func(Token, error)) {
+	for _, sh := range s.shards {
+		sh.notifyDrop(fn)
+	}
+}
+
+func (s *sharded[T]) Update(ctx context.Context, token Token, attrs Attributes) error {
+	return s.shardOf(token).Update(ctx, token, attrs)
+}
+
+func (s *sharded[T]) Move(ctx context.Context, token Token, level int) error {
+	return s.shardOf(token).Move(ctx, token, level)
+}
+
+func (s *sharded[T]) Tick(ctx context.Context, now time.Time) {
+	for _, sh := range s.shards {
+		sh.Tick(ctx, now)
+	}
+}
+
+// Stats 汇总所有分片的统计：计数与长度求和，延迟分布合并后重新计算分位数，HeadWait 取各分片最大值。
+func (s *sharded[T]) Stats(ctx context.Context) Stats {
+	_ = ctx
+	var (
+		out        Stats
+		wait       []histogram
+		turnaround []histogram
+	)
+	for i, sh := range s.shards {
+		sh.mu.Lock()
+		st := sh.statsLocked()
+		if i == 0 {
+			out = st
+			wait = make([]histogram, st.Levels)
+			turnaround = make([]histogram, st.Levels)
+		} else {
+			mergeStats(&out, &st)
+		}
+		for level := range st.Levels {
+			wait[level].merge(&sh.wait[level])
+			turnaround[level].merge(&sh.turnaround[level])
+		}
+		sh.mu.Unlock()
+	}
+	for level := range out.Levels {
+		out.Wait[level] = wait[level].stats()
+		out.Turnaround[level] = turnaround[level].stats()
+	}
+	return out
+}
+
+func mergeStats(dst, src *Stats) {
+	dst.TotalLen += src.TotalLen
+	dst.Held += src.Held
+	dst.Delayed += src.Delayed
+	for i := range dst.ByLevel {
+		dst.ByLevel[i] += src.ByLevel[i]
+		dst.HeadWait[i] = max(dst.HeadWait[i], src.HeadWait[i])
+	}
+	dst.Submitted += src.Submitted
+	dst.Dequeued += src.Dequeued
+	dst.Finished += src.Finished
+	dst.Requeued += src.Requeued
+	dst.Promoted += src.Promoted
+	dst.Demoted += src.Demoted
+	dst.AgingPromoted += src.AgingPromoted
+	dst.Moved += src.Moved
+	dst.Canceled += src.Canceled
+	dst.LeaseExpired += src.LeaseExpired
+	dst.Preempted += src.Preempted
+	dst.Rejected += src.Rejected
+	dst.Evicted += src.Evicted
+	for name, ts := range src.Tenants {
+		agg := dst.Tenants[name]
+		agg.Queued += ts.Queued
+		agg.Leased += ts.Leased
+		agg.Live += ts.Live
+		agg.Submitted += ts.Submitted
+		agg.Finished += ts.Finished
+		agg.Canceled += ts.Canceled
+		agg.Rejected += ts.Rejected
+		dst.Tenants[name] = agg
+	}
+	for i := range dst.BitMapWords {
+		dst.BitMapWords[i] |= src.BitMapWords[i]
+	}
+}
+
+// shardedSnapshot 是分片调度器的快照格式：每个分片一份 snapshotFile。
+type shardedSnapshot struct {
+	Version int             `json:"version"`
+	Shards  []*snapshotFile `json:"shards"`
+}
+
+// ForgetTenant 在所有分片中清除租户的统计（带依赖的任务可能位于任意分片）；
+// 任一分片中租户仍持有任务时返回 false 且不做修改。
+func (s *sharded[T]) ForgetTenant(tenant string) bool {
+	s.lockAll()
+	defer s.unlockAll()
+	for _, sh := range s.shards {
+		if ts := sh.tenants[tenant]; ts != nil && ts.live > 0 {
+			return false
+		}
+	}
+	for _, sh := range s.shards {
+		sh.forgetTenantLocked(tenant)
+	}
+	return true
+}
+
+func (s *sharded[T]) lockAll() {
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+	}
+}
+
+func (s *sharded[T]) unlockAll() {
+	for _, sh := range s.shards {
+		sh.mu.Unlock()
+	}
+}
+
+// Snapshot 在同时持有所有分片锁的情况下写出一致的快照；只能由分片数相同的 NewSharded 调度器 Restore。
+func (s *sharded[T]) Snapshot(w io.Writer) error {
+	if s.shards[0].codec == nil {
+		return ErrNoCodec
+	}
+
+	s.lockAll()
+	file := shardedSnapshot{Version: snapshotVersion, Shards: make([]*snapshotFile, len(s.shards))}
+	for i, sh := range s.shards {
+		if sh.closed {
+			s.unlockAll()
+			return ErrClosed
+		}
+		f, err := sh.snapshotLocked()
+		if err != nil {
+			s.unlockAll()
+			return err
+		}
+		file.Shards[i] = f
+	}
+	s.unlockAll()
+	return json.NewEncoder(w).Encode(&file)
+}
+
+func (s *sharded[T]) Restore(r io.Reader) error {
+	if s.shards[0].codec == nil {
+		return ErrNoCodec
+	}
+
+	var file shardedSnapshot
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return fmt.Errorf("mlfq: decode snapshot: %w", err)
+	}
+	if file.Version != snapshotVersion {
+		return fmt.Errorf("mlfq: unsupported snapshot version %d", file.Version)
+	}
+	if len(file.Shards) != len(s.shards) {
+		return fmt.Errorf("mlfq: snapshot has %d shards, want %d", len(file.Shards), len(s.shards))
+	}
+	tasks := make([][]T, len(s.shards))
+	for i, sh := range s.shards {
+		if file.Shards[i] == nil {
+			return fmt.Errorf("mlfq: snapshot shard %d missing", i)
+		}
+		t, err := sh.decodeSnapshot(file.Shards[i])
+		if err != nil {
+			return err
+		}
+		tasks[i] = t
+	}
+
+	s.lockAll()
+	defer s.unlockAll()
+	for _, sh := range s.shards {
+		if sh.closed {
+			return ErrClosed
+		}
+		if len(sh.states) > 0 {
+			return ErrNotEmpty
+		}
+	}
+	for i, sh := range s.shards {
+		sh.restoreFileLocked(file.Shards[i], tasks[i])
+	}
+	return nil
+}
+
+// Replay 按 token 将每个日志事件路由到所属分片并应用。
+func (s *sharded[T]) Replay(r io.Reader) error {
+	if s.shards[0].codec == nil {
+		return ErrNoCodec
+	}
+
+	dec := json.NewDecoder(r)
+	for {
+		var ev journalEvent
+		if err := dec.Decode(&ev); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("mlfq: decode journal: %w", err)
+		}
+		tok, ok := ev.routeToken()
+		if !ok {
+			continue
+		}
+		if err := s.shardOf(tok).applyEvent(&ev); err != nil {
+			return err
+		}
+	}
+}
+
+// Close 关闭所有分片并唤醒 NextWait 等待者；返回首个分片关闭错误。
+func (s *sharded[T]) Close() error {
+	s.closed.Store(true)
+	var first error
+	for _, sh := range s.shards {
+		if err := sh.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	s.notify()
+	return first
+}
@@ -0,0 +1,166 @@
+package mlfq
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestSharded(t *testing.T, shards int, opts ...Option) Scheduler[string] {
+	t.Helper()
+	s, err := NewSharded(shards, func() Policy[string] {
+		return NewDefaultPolicy[string](3, DefaultPolicyConfig{AgingThreshold: 5 * time.Second})
+	}, opts...)
+	if err != nil {
+		t.Fatalf("new sharded: %v", err)
+	}
+	return s
+}
+
+func TestSharded_RoutingAndSteal(t *testing.T) {
+	ctx := context.Background()
+	s := newTestSharded(t, 4)
+	defer s.Close()
+
+	seen := make(map[Token]bool)
+	for range 40 {
+		tok, err := s.Submit(ctx, "x")
+		if err != nil || seen[tok] {
+			t.Fatalf("submit: tok=%d err=%v", tok, err)
+		}
+		seen[tok] = true
+	}
+	// 同一租户固定在一个分片。
+	var tenantShard uint64
+	for i := range 5 {
+		tok, _ := s.Submit(ctx, "t", WithTenant("t1"))
+		if i == 0 {
+			tenantShard = uint64(tok) % 4
+		} else if uint64(tok)%4 != tenantShard {
+			t.Fatalf("tenant task routed to shard %d want %d", uint64(tok)%4, tenantShard)
+		}
+	}
+
+	// 单个消费者也能取完所有分片的任务。
+	n := 0
+	for {
+		lease, ok := s.Next(ctx)
+		if !ok {
+			break
+		}
+		if err := s.FeedBack(ctx, lease.Token, Feedback{Finished: true}); err != nil {
+			t.Fatalf("feedback: %v", err)
+		}
+		n++
+	}
+	st := s.Stats(ctx)
+	if n != 45 || st.Finished != 45 || st.Submitted != 45 || st.TotalLen != 0 {
+		t.Fatalf("expected 45 finished got n=%d stats=%+v", n, st)
+	}
+	// 租户的任务全部完成后统计保留，ForgetTenant 后清除。
+	if ts := st.Tenants["t1"]; ts.Finished != 5 || ts.Live != 0 || st.Wait[2].Count != 45 {
+		t.Fatalf("unexpected aggregated stats %+v", st)
+	}
+	if !s.ForgetTenant("t1") {
+		t.Fatalf("expected idle tenant forgotten")
+	}
+	if _, ok := s.Stats(ctx).Tenants["t1"]; ok {
+		t.Fatalf("expected tenant stats cleared")
+	}
+	if err := s.Cancel(ctx, 1); err != ErrUnknownToken {
+		t.Fatalf("expected ErrUnknownToken got %v", err)
+	}
+}
+
+func TestSharded_NextWait(t *testing.T) {
+	ctx := context.Background()
+	s := newTestSharded(t, 4)
+
+	var wg sync.WaitGroup
+	got := make(chan string, 8)
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lease, err := s.NextWait(ctx)
+			if err != nil {
+				return
+			}
+			got <- lease.Task
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	for range 4 {
+		_, _ = s.Submit(ctx, "x")
+	}
+	for range 4 {
+		select {
+		case <-got:
+		case <-time.After(time.Second):
+			t.Fatalf("waiter not woken")
+		}
+	}
+	_ = s.Close()
+	wg.Wait()
+	if _, err := s.NextWait(ctx); err != ErrClosed {
+		t.Fatalf("expected ErrClosed got %v", err)
+	}
+}
+
+func TestSharded_SnapshotJournal(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Unix(100, 0)
+	var snap, log bytes.Buffer
+	codec := WithCodec[string](JSONCodec[string]{})
+	s := newTestSharded(t, 3, WithClock(func() time.Time { return clock }), codec, WithJournal(&log))
+
+	used := make(map[Token]bool)
+	for range 6 {
+		tok, _ := s.Submit(ctx, "snap", WithAttributes(levelAttrs(1)))
+		used[tok] = true
+	}
+	if err := s.Snapshot(&snap); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	lease, _ := s.Next(ctx)
+	_ = s.FeedBack(ctx, lease.Token, Feedback{Finished: true})
+	for range 3 {
+		tok, _ := s.Submit(ctx, "log", WithAttributes(levelAttrs(2)))
+		used[tok] = true
+	}
+	want := s.Stats(ctx)
+	_ = s.Close()
+
+	r := newTestSharded(t, 3, WithClock(func() time.Time { return clock }), codec)
+	defer r.Close()
+	if err := r.Restore(&snap); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if err := r.Replay(&log); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	got := r.Stats(ctx)
+	for i := range want.ByLevel {
+		if got.ByLevel[i] != want.ByLevel[i] {
+			t.Fatalf("expected by level %v got %v", want.ByLevel, got.ByLevel)
+		}
+	}
+	// 恢复后分配的 token 不与旧 token 冲突（新任务随机落在任一分片）。
+	for range 9 {
+		tok, _ := r.Submit(ctx, "new")
+		if used[tok] {
+			t.Fatalf("token %d reused after restore", tok)
+		}
+		used[tok] = true
+	}
+
+	other := newTestSharded(t, 2, codec)
+	defer other.Close()
+	var buf bytes.Buffer
+	_ = r.Snapshot(&buf)
+	if err := other.Restore(&buf); err == nil {
+		t.Fatalf("expected shard count mismatch error")
+	}
+}
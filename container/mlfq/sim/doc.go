@@ -0,0 +1,10 @@
+// Package sim 提供 mlfq 策略调参用的离散事件模拟器。
+//
+// 模拟器使用 mlfq.WithClock 注入虚拟时钟，按事件（到达、切片结束、IO 完成、Tick）推进时间，
+// 不依赖真实时间，结果可复现。
+//
+// 特性：
+//   - Generator 按到达间隔分布与任务类别（CPU 密集 / IO 密集等）生成合成负载
+//   - Run 以任意 mlfq.Policy 驱动模拟，输出响应时间、周转时间、饥饿、吞吐与公平性指标
+//   - Compare/Table 在同一负载上并排对比多个策略
+package sim
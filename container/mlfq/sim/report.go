@@ -0,0 +1,191 @@
+package sim
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/arknights-w/go-utils/container/mlfq"
+)
+
+// Summary 是一组时长的分布摘要。
+type Summary struct {
+	Mean time.Duration
+	P50  time.Duration
+	P95  time.Duration
+	P99  time.Duration
+	Max  time.Duration
+}
+
+func summarize(ds []time.Duration) Summary {
+	if len(ds) == 0 {
+		return Summary{}
+	}
+	slices.Sort(ds)
+	var sum time.Duration
+	for _, d := range ds {
+		sum += d
+	}
+	at := func(q float64) time.Duration {
+		return ds[min(int(q*float64(len(ds))), len(ds)-1)]
+	}
+	return Summary{
+		Mean: sum / time.Duration(len(ds)),
+		P50:  at(0.50),
+		P95:  at(0.95),
+		P99:  at(0.99),
+		Max:  ds[len(ds)-1],
+	}
+}
+
+// ClassReport 是单个任务类别的指标。
+type ClassReport struct {
+	Jobs      int
+	Completed int
+	// Response 是到达到首次运行的时长分布（仅统计已开始运行的任务）。
+	Response Summary
+	// Turnaround 是到达到完成的时长分布（仅统计已完成的任务）。
+	Turnaround Summary
+	// MaxWait 是单次就绪等待的最长时长。
+	MaxWait time.Duration
+	// Starved 是单次就绪等待超过 Config.StarvationThreshold 的任务数（含未完成的任务）。
+	Starved int
+}
+
+// Report 是一次模拟的结果。
+type Report struct {
+	// Policy 是策略名称（由 Compare 填写）。
+	Policy string
+	ClassReport
+	// Makespan 是模拟结束时的虚拟时间。
+	Makespan time.Duration
+	// Throughput 是每秒完成的任务数。
+	Throughput float64
+	// Utilization 是 CPU 忙碌时间占比（0..1）。
+	Utilization float64
+	// Fairness 是已完成任务“服务时间/周转时间”的 Jain 公平指数（0..1，越大越公平）。
+	Fairness float64
+	// ByClass 是按 Job.Class 划分的指标。
+	ByClass map[string]ClassReport
+	// Stats 是模拟结束时的调度器统计。
+	Stats mlfq.Stats
+}
+
+type classAcc struct {
+	jobs, completed, starved int
+	response, turnaround     []time.Duration
+	maxWait                  time.Duration
+}
+
+func (a *classAcc) add(t *Task, now, threshold time.Duration) {
+	a.jobs++
+	wait := t.maxWait
+	if !t.started {
+		// 至模拟结束仍未运行：等待时长按结束时间计算。
+		wait = now - t.Job.Arrival
+	}
+	a.maxWait = max(a.maxWait, wait)
+	if wait > threshold {
+		a.starved++
+	}
+	if t.started {
+		a.response = append(a.response, t.firstRun-t.Job.Arrival)
+	}
+	if t.remaining <= 0 {
+		a.completed++
+		a.turnaround = append(a.turnaround, t.finish-t.Job.Arrival)
+	}
+}
+
+func (a *classAcc) report() ClassReport {
+	return ClassReport{
+		Jobs:       a.jobs,
+		Completed:  a.completed,
+		Response:   summarize(a.response),
+		Turnaround: summarize(a.turnaround),
+		MaxWait:    a.maxWait,
+		Starved:    a.starved,
+	}
+}
+
+func (s *simulator) report() Report {
+	var all classAcc
+	by := make(map[string]*classAcc)
+	var sum, sumSq float64
+	for _, t := range s.tasks {
+		if t.Job.Arrival > s.now {
+			continue
+		}
+		all.add(t, s.now, s.cfg.StarvationThreshold)
+		acc, ok := by[t.Job.Class]
+		if !ok {
+			acc = &classAcc{}
+			by[t.Job.Class] = acc
+		}
+		acc.add(t, s.now, s.cfg.StarvationThreshold)
+		if t.remaining <= 0 {
+			x := float64(t.Job.Service) / float64(max(t.finish-t.Job.Arrival, 1))
+			sum += x
+			sumSq += x * x
+		}
+	}
+
+	r := Report{
+		ClassReport: all.report(),
+		Makespan:    s.now,
+		ByClass:     make(map[string]ClassReport, len(by)),
+		Stats:       s.q.Stats(context.Background()),
+	}
+	for name, acc := range by {
+		r.ByClass[name] = acc.report()
+	}
+	if s.now > 0 {
+		r.Throughput = float64(all.completed) / s.now.Seconds()
+		r.Utilization = float64(min(s.busy, s.now*time.Duration(s.cfg.CPUs))) / float64(s.now*time.Duration(s.cfg.CPUs))
+	}
+	if sumSq > 0 {
+		r.Fairness = sum * sum / (float64(all.completed) * sumSq)
+	}
+	return r
+}
+
+// NamedPolicy 是参与对比的策略：New 每次返回一个新的策略实例。
+type NamedPolicy struct {
+	Name string
+	New  func() mlfq.Policy[*Task]
+}
+
+// Compare 在同一负载上依次运行每个策略，返回与 policies 顺序一致的结果。
+func Compare(cfg Config, jobs []Job, policies ...NamedPolicy) ([]Report, error) {
+	out := make([]Report, 0, len(policies))
+	for _, p := range policies {
+		r, err := Run(cfg, jobs, p.New())
+		if err != nil {
+			return nil, fmt.Errorf("sim: policy %s: %w", p.Name, err)
+		}
+		r.Policy = p.Name
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// Table 将多个结果格式化为并排对比的文本表格；class 非空时只展示该类别的延迟指标。
+func Table(reports []Report, class string) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "policy\tdone\tthroughput/s\tutil\tfairness\tresp p50\tresp p99\tturn p50\tturn p99\tmax wait\tstarved\t")
+	for _, r := range reports {
+		c := r.ClassReport
+		if class != "" {
+			c = r.ByClass[class]
+		}
+		fmt.Fprintf(w, "%s\t%d/%d\t%.1f\t%.2f\t%.3f\t%v\t%v\t%v\t%v\t%v\t%d\t\n",
+			r.Policy, c.Completed, c.Jobs, r.Throughput, r.Utilization, r.Fairness,
+			c.Response.P50, c.Response.P99, c.Turnaround.P50, c.Turnaround.P99, c.MaxWait, c.Starved)
+	}
+	_ = w.Flush()
+	return b.String()
+}
@@ -0,0 +1,265 @@
+package sim
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arknights-w/go-utils/container/mlfq"
+	"github.com/arknights-w/go-utils/container/pqueue"
+)
+
+// Task 是提交给调度器的任务对象，策略可通过 Job 读取任务信息。
+type Task struct {
+	Job Job
+
+	remaining time.Duration // 剩余 CPU 时间
+	burstLeft time.Duration // 本次突发剩余 CPU 时间
+	readyAt   time.Duration // 最近一次进入就绪队列的时间
+	firstRun  time.Duration
+	started   bool
+	maxWait   time.Duration
+	finish    time.Duration
+	slices    int
+}
+
+// Config 是模拟参数。
+type Config struct {
+	// CPUs 是并发执行的消费者数，<=0 时按 1 处理。
+	CPUs int
+	// TickEvery 是调用 Tick 的间隔，<=0 表示不 Tick（不老化）。
+	TickEvery time.Duration
+	// Horizon 是模拟时长上限，<=0 表示运行到所有任务完成。
+	Horizon time.Duration
+	// MaxIdleTicks 是只剩 Tick 事件、没有任务在执行且 Tick 不再改变调度器状态时最多空转的 Tick 数，
+	// 超出后视为死锁（策略不再发放剩余任务）；<=0 时按 1000 处理。
+	MaxIdleTicks int
+	// StarvationThreshold 是判定饥饿的单次就绪等待时长，<=0 时按 1s 处理。
+	StarvationThreshold time.Duration
+	// Options 是额外的调度器选项（时钟由模拟器注入）。
+	Options []mlfq.Option
+}
+
+// 事件类型。
+const (
+	evArrival = iota
+	evSliceEnd
+	evIODone
+	evTick
+)
+
+type event struct {
+	kind int
+	task *Task
+	cpu  int
+	tok  mlfq.Token
+	// epoch 是本次 Lease 的 Epoch，随 FeedBack 回传。
+	epoch uint64
+	ran   time.Duration
+}
+
+type eventKey struct {
+	at  time.Duration
+	seq uint64
+}
+
+type simulator struct {
+	cfg    Config
+	start  time.Time
+	now    time.Duration
+	q      mlfq.Scheduler[*Task]
+	events *pqueue.Queue[event, eventKey]
+	seq    uint64
+	idle   []int
+	busy   time.Duration
+	done   int
+	tasks  []*Task
+	// idleTicks 是连续空转的 Tick 数（见 Config.MaxIdleTicks）。
+	idleTicks int
+}
+
+// progress 是判断 Tick 是否改变了调度器状态所用的统计摘要。
+type progress struct {
+	totalLen, held, delayed              int
+	dequeued, agingPromoted, moved, lost uint64
+}
+
+// Run 以 policy 运行一次模拟。每次调用都应传入新的策略实例（策略可能有内部状态）。
+func Run(cfg Config, jobs []Job, policy mlfq.Policy[*Task]) (Report, error) {
+	if cfg.CPUs <= 0 {
+		cfg.CPUs = 1
+	}
+	if cfg.StarvationThreshold <= 0 {
+		cfg.StarvationThreshold = time.Second
+	}
+	if cfg.MaxIdleTicks <= 0 {
+		cfg.MaxIdleTicks = 1000
+	}
+
+	s := &simulator{
+		cfg:   cfg,
+		start: time.Unix(0, 0),
+		events: pqueue.New[event](func(a, b eventKey) bool {
+			return a.at < b.at || (a.at == b.at && a.seq < b.seq)
+		}),
+	}
+	opts := append([]mlfq.Option{}, cfg.Options...)
+	opts = append(opts, mlfq.WithClock(func() time.Time { return s.start.Add(s.now) }))
+	q, err := mlfq.New(policy, opts...)
+	if err != nil {
+		return Report{}, err
+	}
+	defer q.Close()
+	s.q = q
+
+	for i := range cfg.CPUs {
+		s.idle = append(s.idle, i)
+	}
+	for _, job := range jobs {
+		t := &Task{Job: job, remaining: job.Service, burstLeft: job.Burst}
+		s.tasks = append(s.tasks, t)
+		s.push(job.Arrival, event{kind: evArrival, task: t})
+	}
+	if cfg.TickEvery > 0 && len(jobs) > 0 {
+		s.push(cfg.TickEvery, event{kind: evTick})
+	}
+
+	if err := s.loop(); err != nil {
+		return Report{}, err
+	}
+	return s.report(), nil
+}
+
+func (s *simulator) push(at time.Duration, ev event) {
+	s.seq++
+	s.events.Push(ev, eventKey{at: at, seq: s.seq})
+}
+
+func (s *simulator) loop() error {
+	ctx := context.Background()
+	for s.done < len(s.tasks) {
+		if err := s.dispatch(ctx); err != nil {
+			return err
+		}
+		ev, key, ok := s.events.Pop()
+		if !ok {
+			return s.deadlock()
+		}
+		if s.cfg.Horizon > 0 && key.at > s.cfg.Horizon {
+			s.now = s.cfg.Horizon
+			return nil
+		}
+		s.now = key.at
+
+		switch ev.kind {
+		case evArrival:
+			ev.task.readyAt = s.now
+			if _, err := s.q.Submit(ctx, ev.task, mlfq.WithAttributes(ev.task.Job.Attrs)); err != nil {
+				return fmt.Errorf("sim: submit job %d: %w", ev.task.Job.ID, err)
+			}
+		case evSliceEnd:
+			s.idle = append(s.idle, ev.cpu)
+			if err := s.sliceEnd(ctx, ev); err != nil {
+				return err
+			}
+		case evIODone:
+			ev.task.burstLeft = ev.task.Job.Burst
+			if err := s.feedback(ctx, ev.task, ev.tok, ev.epoch, ev.ran, false); err != nil {
+				return err
+			}
+		case evTick:
+			// 只剩 Tick 事件且没有任务在执行时，检查本次 Tick 是否改变了调度器状态。
+			stalled := s.events.Len() == 0 && len(s.idle) == s.cfg.CPUs
+			var before progress
+			if stalled {
+				before = s.progress(ctx)
+			}
+			s.q.Tick(ctx, s.start.Add(s.now))
+			if stalled && s.progress(ctx) == before {
+				if s.idleTicks++; s.idleTicks > s.cfg.MaxIdleTicks {
+					return s.deadlock()
+				}
+			} else {
+				s.idleTicks = 0
+			}
+			s.push(s.now+s.cfg.TickEvery, event{kind: evTick})
+		}
+	}
+	return nil
+}
+
+func (s *simulator) deadlock() error {
+	return fmt.Errorf("sim: deadlock with %d unfinished tasks", len(s.tasks)-s.done)
+}
+
+func (s *simulator) progress(ctx context.Context) progress {
+	st := s.q.Stats(ctx)
+	return progress{
+		totalLen:      st.TotalLen,
+		held:          st.Held,
+		delayed:       st.Delayed,
+		dequeued:      st.Dequeued,
+		agingPromoted: st.AgingPromoted,
+		moved:         st.Moved,
+		lost:          st.LeaseExpired + st.Canceled + st.Evicted,
+	}
+}
+
+// dispatch 为所有空闲 CPU 取出任务并安排切片结束事件。
+func (s *simulator) dispatch(ctx context.Context) error {
+	for len(s.idle) > 0 {
+		lease, ok := s.q.Next(ctx)
+		if !ok {
+			return nil
+		}
+		cpu := s.idle[len(s.idle)-1]
+		s.idle = s.idle[:len(s.idle)-1]
+
+		t := lease.Task
+		if !t.started {
+			t.started = true
+			t.firstRun = s.now
+		}
+		t.maxWait = max(t.maxWait, s.now-t.readyAt)
+		t.slices++
+
+		run := t.remaining
+		if t.Job.Burst > 0 {
+			run = min(run, t.burstLeft)
+		}
+		if lease.Quantum > 0 {
+			run = min(run, lease.Quantum)
+		}
+		s.busy += run
+		s.push(s.now+run, event{kind: evSliceEnd, task: t, cpu: cpu, tok: lease.Token, epoch: lease.Epoch, ran: run})
+	}
+	return nil
+}
+
+func (s *simulator) sliceEnd(ctx context.Context, ev event) error {
+	t := ev.task
+	t.remaining -= ev.ran
+	if t.Job.Burst > 0 {
+		t.burstLeft -= ev.ran
+	}
+	switch {
+	case t.remaining <= 0:
+		t.finish = s.now
+		s.done++
+		return s.feedback(ctx, t, ev.tok, ev.epoch, ev.ran, true)
+	case t.Job.Burst > 0 && t.burstLeft <= 0:
+		// 进入 IO：CPU 立即释放，Lease 保持到 IO 完成再 FeedBack（未用满时间片）。
+		s.push(s.now+t.Job.IOWait, event{kind: evIODone, task: t, tok: ev.tok, epoch: ev.epoch, ran: ev.ran})
+		return nil
+	default:
+		return s.feedback(ctx, t, ev.tok, ev.epoch, ev.ran, false)
+	}
+}
+
+func (s *simulator) feedback(ctx context.Context, t *Task, tok mlfq.Token, epoch uint64, ran time.Duration, finished bool) error {
+	t.readyAt = s.now
+	if err := s.q.FeedBack(ctx, tok, mlfq.Feedback{Epoch: epoch, RanFor: ran, Finished: finished}); err != nil {
+		return fmt.Errorf("sim: feedback job %d: %w", t.Job.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package sim
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arknights-w/go-utils/container/mlfq"
+)
+
+func defaultPolicy(aging time.Duration) func() mlfq.Policy[*Task] {
+	return func() mlfq.Policy[*Task] {
+		return mlfq.NewDefaultPolicy[*Task](6, mlfq.DefaultPolicyConfig{
+			BaseQuantum:    2 * time.Millisecond,
+			MaxQuantum:     100 * time.Millisecond,
+			AgingThreshold: aging,
+		})
+	}
+}
+
+func TestRun_CPUIOMix(t *testing.T) {
+	jobs := CPUIOMix(1, 300).Jobs()
+	cfg := Config{CPUs: 2, TickEvery: 10 * time.Millisecond}
+	r, err := Run(cfg, jobs, defaultPolicy(200*time.Millisecond)())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if r.Completed != len(jobs) || r.Stats.Finished != uint64(len(jobs)) {
+		t.Fatalf("expected all %d jobs completed got %d", len(jobs), r.Completed)
+	}
+	if r.Utilization <= 0 || r.Utilization > 1 || r.Fairness <= 0 || r.Fairness > 1 {
+		t.Fatalf("unexpected utilization/fairness %+v", r)
+	}
+	// MLFQ 应优先服务 IO 密集的短突发任务。
+	io, cpu := r.ByClass["io"], r.ByClass["cpu"]
+	if io.Jobs == 0 || cpu.Jobs == 0 || io.Response.P50 >= cpu.Response.P50 && io.Turnaround.P50 >= cpu.Turnaround.P50 {
+		t.Fatalf("expected io jobs favored: io=%+v cpu=%+v", io, cpu)
+	}
+
+	again, _ := Run(cfg, jobs, defaultPolicy(200*time.Millisecond)())
+	if again.Makespan != r.Makespan || again.Turnaround != r.Turnaround {
+		t.Fatalf("simulation not deterministic")
+	}
+}
+
+func TestCompare_AgingReducesStarvation(t *testing.T) {
+	// 持续到达的短任务占满高优先级，没有老化时长任务会被饿死。
+	gen := Generator{
+		Seed:         7,
+		Count:        400,
+		Interarrival: Const(5 * time.Millisecond),
+		Classes: []Class{
+			{Name: "long", Weight: 0.05, Service: Const(300 * time.Millisecond)},
+			{Name: "short", Weight: 0.95, Service: Const(4 * time.Millisecond)},
+		},
+	}
+	cfg := Config{TickEvery: 10 * time.Millisecond, StarvationThreshold: 500 * time.Millisecond}
+	reports, err := Compare(cfg, gen.Jobs(),
+		NamedPolicy{Name: "no-aging", New: defaultPolicy(time.Hour)},
+		NamedPolicy{Name: "aging", New: defaultPolicy(100 * time.Millisecond)},
+	)
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+	noAging, aging := reports[0].ByClass["long"], reports[1].ByClass["long"]
+	if aging.MaxWait >= noAging.MaxWait {
+		t.Fatalf("expected aging to reduce max wait: aging=%v no-aging=%v", aging.MaxWait, noAging.MaxWait)
+	}
+	t.Log("\n" + Table(reports, "long"))
+}
+
+func TestRun_Horizon(t *testing.T) {
+	jobs := CPUIOMix(3, 100).Jobs()
+	r, err := Run(Config{Horizon: 100 * time.Millisecond}, jobs, defaultPolicy(time.Second)())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if r.Makespan != 100*time.Millisecond || r.Completed >= len(jobs) || r.Jobs == 0 {
+		t.Fatalf("unexpected horizon report %+v", r.ClassReport)
+	}
+}
+
+// stuckPolicy 从不发放 level 1 的任务。
+type stuckPolicy struct {
+	*mlfq.DefaultPolicy[*Task]
+}
+
+func (p stuckPolicy) PickNext(_ time.Time, q mlfq.QueueView) (int, bool) {
+	return 0, q.Len(0) > 0
+}
+
+func TestRun_DeadlockWithTicks(t *testing.T) {
+	// 零值属性的任务进入 level 1，且不会被老化提升
+	jobs := []Job{{ID: 1, Service: time.Millisecond}, {ID: 2, Service: time.Millisecond}}
+	p := stuckPolicy{mlfq.NewDefaultPolicy[*Task](2, mlfq.DefaultPolicyConfig{AgingThreshold: time.Hour})}
+	_, err := Run(Config{TickEvery: time.Millisecond, MaxIdleTicks: 10}, jobs, p)
+	if err == nil || !strings.Contains(err.Error(), "deadlock") {
+		t.Fatalf("expected deadlock error got %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+package sim
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/arknights-w/go-utils/container/mlfq"
+)
+
+// Job 是一个模拟任务。
+type Job struct {
+	ID int
+	// Class 是任务类别，用于分组统计。
+	Class string
+	// Arrival 是相对模拟开始的到达时间。
+	Arrival time.Duration
+	// Service 是任务总共需要的 CPU 时间。
+	Service time.Duration
+	// Burst 是每次进入 IO 前连续使用 CPU 的时长；0 表示纯 CPU 任务（从不阻塞）。
+	Burst time.Duration
+	// IOWait 是每次 IO 阻塞的时长。
+	IOWait time.Duration
+	// Attrs 是提交时的任务属性。
+	Attrs mlfq.Attributes
+}
+
+// Dist 是时长分布。
+type Dist interface {
+	Sample(r *rand.Rand) time.Duration
+}
+
+type constDist time.Duration
+
+func (d constDist) Sample(*rand.Rand) time.Duration { return time.Duration(d) }
+
+// Const 返回恒为 d 的分布。
+func Const(d time.Duration) Dist { return constDist(d) }
+
+type uniformDist struct{ lo, hi time.Duration }
+
+func (d uniformDist) Sample(r *rand.Rand) time.Duration {
+	if d.hi <= d.lo {
+		return d.lo
+	}
+	return d.lo + time.Duration(r.Int64N(int64(d.hi-d.lo)))
+}
+
+// Uniform 返回 [lo, hi) 上的均匀分布。
+func Uniform(lo, hi time.Duration) Dist { return uniformDist{lo, hi} }
+
+type expDist time.Duration
+
+func (d expDist) Sample(r *rand.Rand) time.Duration {
+	return time.Duration(r.ExpFloat64() * float64(d))
+}
+
+// Exp 返回均值为 mean 的指数分布（用作到达间隔即为泊松到达）。
+func Exp(mean time.Duration) Dist { return expDist(mean) }
+
+// Class 描述一类任务。
+type Class struct {
+	Name string
+	// Weight 是该类任务在负载中的相对比例。
+	Weight  float64
+	Service Dist
+	// Burst/IOWait 为 nil 时表示纯 CPU 任务。
+	Burst  Dist
+	IOWait Dist
+	Attrs  mlfq.Attributes
+}
+
+// Generator 生成合成负载。
+type Generator struct {
+	// Seed 决定随机序列，相同 Seed 生成相同负载。
+	Seed uint64
+	// Count 是任务数。
+	Count int
+	// Interarrival 是相邻任务的到达间隔分布；nil 表示全部在 0 时刻到达。
+	Interarrival Dist
+	Classes      []Class
+}
+
+// Jobs 生成按到达时间排序的任务列表。
+func (g Generator) Jobs() []Job {
+	r := rand.New(rand.NewPCG(g.Seed, g.Seed^0x9e3779b97f4a7c15))
+	var total float64
+	for _, c := range g.Classes {
+		total += c.Weight
+	}
+	jobs := make([]Job, 0, g.Count)
+	var at time.Duration
+	for i := range g.Count {
+		if g.Interarrival != nil && i > 0 {
+			at += g.Interarrival.Sample(r)
+		}
+		c := pickClass(r, g.Classes, total)
+		job := Job{
+			ID:      i,
+			Class:   c.Name,
+			Arrival: at,
+			Service: max(c.Service.Sample(r), time.Microsecond),
+			Attrs:   c.Attrs,
+		}
+		if c.Burst != nil {
+			job.Burst = max(c.Burst.Sample(r), time.Microsecond)
+			if c.IOWait != nil {
+				job.IOWait = c.IOWait.Sample(r)
+			}
+		}
+		jobs = append(jobs, job)
+	}
+	slices.SortStableFunc(jobs, func(a, b Job) int { return cmp.Compare(a.Arrival, b.Arrival) })
+	return jobs
+}
+
+func pickClass(r *rand.Rand, classes []Class, total float64) Class {
+	x := r.Float64() * total
+	for _, c := range classes {
+		if x < c.Weight {
+			return c
+		}
+		x -= c.Weight
+	}
+	return classes[len(classes)-1]
+}
+
+// CPUIOMix 返回一个典型的混合负载：
+// 约 20% 的 CPU 密集任务（长服务时间、从不阻塞）与 80% 的 IO 密集任务（短突发后阻塞等待 IO），泊松到达。
+func CPUIOMix(seed uint64, count int) Generator {
+	return Generator{
+		Seed:         seed,
+		Count:        count,
+		Interarrival: Exp(20 * time.Millisecond),
+		Classes: []Class{
+			{
+				Name:    "cpu",
+				Weight:  0.2,
+				Service: Uniform(200*time.Millisecond, 2*time.Second),
+			},
+			{
+				Name:    "io",
+				Weight:  0.8,
+				Service: Uniform(5*time.Millisecond, 50*time.Millisecond),
+				Burst:   Uniform(500*time.Microsecond, 3*time.Millisecond),
+				IOWait:  Exp(10 * time.Millisecond),
+			},
+		},
+	}
+}
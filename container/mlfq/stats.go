@@ -10,9 +10,21 @@ type Stats struct {
 	Levels int
 	// TotalLen 是所有层级的总任务数（不包含已发放但未反馈的任务）。
 	TotalLen int
+	// Held 是等待前置任务完成、尚未入队的任务数（见 WithAfter）。
+	Held int
+	// Delayed 是尚未到达 NotBefore、保存在定时结构中的任务数（见 WithNotBefore）。
+	Delayed int
 	// ByLevel 是每个 level 的队列长度快照，下标对应 level。
 	ByLevel []int
 
+	// Wait 是每个 level 的排队等待时间分布（入队到被 Next 取出），下标对应 level。
+	Wait []LatencyStats
+	// Turnaround 是每个 level 的周转时间分布（Submit 到完成），按完成时所在 level 统计。
+	Turnaround []LatencyStats
+	// HeadWait 是每个 level 当前等待最久的任务已等待的时长；空 level 为 0。
+	// 配合 Tick 观察可验证老化是否有效防止饥饿。
+	HeadWait []time.Duration
+
 	// Submitted 是累计提交任务数。
 	Submitted uint64
 	// Dequeued 是累计 Next 成功取出任务数。
@@ -27,7 +39,42 @@ type Stats struct {
 	Demoted uint64
 	// AgingPromoted 是累计 Tick 老化提升次数。
 	AgingPromoted uint64
+	// Moved 是累计 Update/Move 导致排队任务 level 变化的次数。
+	Moved uint64
+	// Canceled 是累计 Cancel 成功取消的任务数。
+	Canceled uint64
+	// LeaseExpired 是累计因 Lease 超时被 Tick 回收的次数。
+	LeaseExpired uint64
+	// Preempted 是累计被抢占的 Lease 数（见 WithPreemption）。
+	Preempted uint64
+	// Rejected 是累计因容量已满被拒绝的提交数（ErrFull）。
+	Rejected uint64
+	// Evicted 是累计被 AdmitEvict 淘汰的任务数。
+	Evicted uint64
+
+	// Tenants 是按租户划分的统计，key 为租户（默认租户为空字符串）。
+	Tenants map[string]TenantStats
 
 	// BitMapWords 是当前多级队列位图的 uint64 words 快照（用于调试）。
 	BitMapWords []uint64
 }
+
+// TenantStats 是单个租户的统计快照。
+//
+// 租户的统计在提交后一直保留，直到调用 ForgetTenant。
+type TenantStats struct {
+	// Queued 是排队中的任务数（不包含已发放但未反馈的任务）。
+	Queued int
+	// Leased 是已发放 Lease、等待 FeedBack 的任务数。
+	Leased int
+	// Live 是调度器持有的任务数（排队、等待前置任务、延迟中与 Lease 中）；Live-Leased 用于配额判断。
+	Live int
+	// Submitted 是累计提交成功的任务数。
+	Submitted uint64
+	// Finished 是累计完成并移除的任务数。
+	Finished uint64
+	// Canceled 是累计被 Cancel 的任务数。
+	Canceled uint64
+	// Rejected 是累计因超出配额被拒绝的提交数。
+	Rejected uint64
+}
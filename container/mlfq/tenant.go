@@ -0,0 +1,84 @@
+package mlfq
+
+// WithTenantQuota 限制每个租户排队中的任务数（含等待前置任务与延迟中的任务，不含已发放 Lease 的任务）；
+// 超出时 Submit 返回 ErrTenantQuota。n<=0 表示不限制。可用 WithTenantQuotaFor 为个别租户单独设置。
+func WithTenantQuota(n int) Option {
+	return func(c *config) {
+		c.tenantQuota = n
+	}
+}
+
+// WithTenantQuotaFor 为指定租户设置配额，优先于 WithTenantQuota；n<=0 表示该租户不限制。
+func WithTenantQuotaFor(tenant string, n int) Option {
+	return func(c *config) {
+		if c.tenantQuotas == nil {
+			c.tenantQuotas = make(map[string]int)
+		}
+		c.tenantQuotas[tenant] = n
+	}
+}
+
+func (c *config) quotaOf(tenant string) int {
+	if n, ok := c.tenantQuotas[tenant]; ok {
+		return n
+	}
+	return c.tenantQuota
+}
+
+// tenantState 是单个租户的计数（需在调度器锁内访问）。
+type tenantState struct {
+	queued    int
+	leased    int
+	live      int
+	submitted uint64
+	finished  uint64
+	canceled  uint64
+	rejected  uint64
+}
+
+// tenantLocked 返回租户的计数，不存在时创建。
+func (s *scheduler[T]) tenantLocked(tenant string) *tenantState {
+	ts, ok := s.tenants[tenant]
+	if !ok {
+		ts = &tenantState{}
+		s.tenants[tenant] = ts
+	}
+	return ts
+}
+
+// pending 返回计入配额的任务数：调度器持有但尚未发放 Lease 的任务。
+func (ts *tenantState) pending() int {
+	return ts.live - ts.leased
+}
+
+// ForgetTenant 清除租户的累计统计，用于租户下线后回收其计数。
+// 租户仍持有任务时返回 false 且不做修改。
+func (s *scheduler[T]) ForgetTenant(tenant string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.forgetTenantLocked(tenant)
+}
+
+func (s *scheduler[T]) forgetTenantLocked(tenant string) bool {
+	if ts := s.tenants[tenant]; ts != nil && ts.live > 0 {
+		return false
+	}
+	delete(s.tenants, tenant)
+	return true
+}
+
+func (s *scheduler[T]) tenantStatsLocked() map[string]TenantStats {
+	out := make(map[string]TenantStats, len(s.tenants))
+	for name, ts := range s.tenants {
+		out[name] = TenantStats{
+			Queued:    ts.queued,
+			Leased:    ts.leased,
+			Live:      ts.live,
+			Submitted: ts.submitted,
+			Finished:  ts.finished,
+			Canceled:  ts.canceled,
+			Rejected:  ts.rejected,
+		}
+	}
+	return out
+}
@@ -0,0 +1,102 @@
+package mlfq
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestScheduler_TenantRoundRobin(t *testing.T) {
+	ctx := context.Background()
+	s, _ := NewDefault[string](4)
+	defer s.Close()
+
+	// 租户 a 先灌入大量任务，b 随后提交少量任务：同一 level 内应交替出队。
+	for range 100 {
+		_, _ = s.Submit(ctx, "a", WithTenant("a"))
+	}
+	for range 3 {
+		_, _ = s.Submit(ctx, "b", WithTenant("b"))
+	}
+
+	want := []string{"a", "b", "a", "b", "a", "b", "a", "a"}
+	for i, w := range want {
+		lease, ok := s.Next(ctx)
+		if !ok || lease.Task != w {
+			t.Fatalf("idx=%d expected %s got %s ok=%v", i, w, lease.Task, ok)
+		}
+	}
+}
+
+func TestScheduler_TenantQuotaAndStats(t *testing.T) {
+	ctx := context.Background()
+	s, _ := NewDefault[string](4, WithTenantQuota(2), WithTenantQuotaFor("vip", 0))
+	defer s.Close()
+
+	_, _ = s.Submit(ctx, "x", WithTenant("t1"))
+	_, _ = s.Submit(ctx, "y", WithTenant("t1"))
+	if _, err := s.Submit(ctx, "z", WithTenant("t1")); err != ErrTenantQuota {
+		t.Fatalf("expected ErrTenantQuota got %v", err)
+	}
+	for range 5 {
+		if _, err := s.Submit(ctx, "v", WithTenant("vip")); err != nil {
+			t.Fatalf("vip should be unlimited: %v", err)
+		}
+	}
+
+	// 已发放 Lease 的任务不占配额：配额只限制排队中的任务。
+	lease, ok := s.Next(ctx)
+	for ok && lease.Task != "x" {
+		lease, ok = s.Next(ctx)
+	}
+	if !ok {
+		t.Fatalf("expected t1 lease")
+	}
+	if _, err := s.Submit(ctx, "z", WithTenant("t1")); err != nil {
+		t.Fatalf("submit while leased: %v", err)
+	}
+	if _, err := s.Submit(ctx, "w", WithTenant("t1")); err != ErrTenantQuota {
+		t.Fatalf("expected ErrTenantQuota got %v", err)
+	}
+	// 延迟中的任务计入配额。
+	_ = s.FeedBack(ctx, lease.Token, Feedback{Finished: true})
+	for ok && lease.Task != "y" {
+		lease, ok = s.Next(ctx)
+	}
+	if _, err := s.Submit(ctx, "d", WithTenant("t1"), WithDelay(time.Hour)); err != nil {
+		t.Fatalf("delayed submit: %v", err)
+	}
+	if _, err := s.Submit(ctx, "w", WithTenant("t1"), WithDelay(time.Hour)); err != ErrTenantQuota {
+		t.Fatalf("expected ErrTenantQuota for delayed got %v", err)
+	}
+
+	st := s.Stats(ctx).Tenants["t1"]
+	if st.Live != 3 || st.Queued != 1 || st.Leased != 1 || st.Submitted != 4 || st.Finished != 1 || st.Rejected != 3 {
+		t.Fatalf("unexpected tenant stats %+v", st)
+	}
+}
+
+func TestScheduler_ForgetTenant(t *testing.T) {
+	ctx := context.Background()
+	s, _ := NewDefault[string](4, WithTenantQuota(1))
+	defer s.Close()
+
+	tok, _ := s.Submit(ctx, "a", WithTenant("a"))
+	if s.ForgetTenant("a") {
+		t.Fatalf("expected tenant with live tasks kept")
+	}
+	_ = s.Cancel(ctx, tok)
+	// 租户空闲后累计统计仍保留。
+	if st, ok := s.Stats(ctx).Tenants["a"]; !ok || st.Submitted != 1 || st.Canceled != 1 {
+		t.Fatalf("unexpected tenant stats %+v ok=%v", st, ok)
+	}
+	if !s.ForgetTenant("a") {
+		t.Fatalf("expected idle tenant forgotten")
+	}
+	if _, ok := s.Stats(ctx).Tenants["a"]; ok {
+		t.Fatalf("expected tenant stats cleared")
+	}
+	if !s.ForgetTenant("unknown") {
+		t.Fatalf("expected unknown tenant treated as idle")
+	}
+}
@@ -0,0 +1,87 @@
+package mlfq
+
+import (
+	"context"
+	"time"
+)
+
+// Update 修改任务属性。
+//
+// 策略实现了 Updater 时，排队中的任务立即通过 OnUpdate 计算新 level，level 变化时移到新 level 队尾（重新计算等待时间），
+// 否则保持当前 level；已发放 Lease 的任务只更新属性，作为下一次 FeedBack 的默认 Attrs。
+func (s *scheduler[T]) Update(ctx context.Context, token Token, attrs Attributes) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+
+	st, ok := s.states[token]
+	if !ok {
+		return ErrUnknownToken
+	}
+	if st.leased {
+		st.attrs = attrs
+		return nil
+	}
+
+	now := s.cfg.now()
+	level := st.level
+	if u, ok := s.policy.(Updater[T]); ok {
+		level = u.OnUpdate(now, st.level, st.task, st.attrs, attrs)
+		if level < 0 || level >= s.mq.Levels() {
+			return ErrInvalidLevel
+		}
+	}
+	st.attrs = attrs
+	s.moveLocked(st, level, now)
+	return nil
+}
+
+// Move 将排队中的任务直接移到 level 队尾（不咨询策略）；已发放 Lease 的任务返回 ErrLeased。
+func (s *scheduler[T]) Move(ctx context.Context, token Token, level int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+
+	if level < 0 || level >= s.mq.Levels() {
+		return ErrInvalidLevel
+	}
+	st, ok := s.states[token]
+	if !ok {
+		return ErrUnknownToken
+	}
+	if st.leased {
+		return ErrLeased
+	}
+	s.moveLocked(st, level, s.cfg.now())
+	return nil
+}
+
+// moveLocked 将排队中的任务移到 level；level 不变时只记录属性变化。
+func (s *scheduler[T]) moveLocked(st *taskState[T], level int, now time.Time) {
+	old := st.level
+	if st.held || st.delayHandle != nil {
+		// 等待前置任务或延迟中的任务只记录目标 level。
+		st.level = level
+	} else if level != old {
+		s.unqueueLocked(st)
+		s.enqueueLocked(st, level, now)
+		s.moved++
+		s.observe(EventMoved, st, old, now, false)
+		if level < old {
+			s.preemptLocked(now)
+		}
+	}
+	s.logUpdate(st, now)
+}
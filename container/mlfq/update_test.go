@@ -0,0 +1,109 @@
+package mlfq
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestScheduler_UpdateRepositions(t *testing.T) {
+	ctx := context.Background()
+	rec := NewRecorder(0)
+	s, _ := NewDefault[string](3, WithObserver(rec.Observe))
+	defer s.Close()
+
+	_, _ = s.Submit(ctx, "a", WithAttributes(levelAttrs(1)))
+	tok, _ := s.Submit(ctx, "b", WithAttributes(levelAttrs(2)))
+	if err := s.Update(ctx, tok, levelAttrs(0)); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	lease, _ := s.Next(ctx)
+	if lease.Task != "b" || lease.Level != 0 {
+		t.Fatalf("expected b at level 0 got %s at %d", lease.Task, lease.Level)
+	}
+	if st := s.Stats(ctx); st.Moved != 1 {
+		t.Fatalf("expected moved=1 got %d", st.Moved)
+	}
+	h := rec.History(tok)
+	if ev := h[len(h)-2]; ev.Kind != EventMoved || ev.OldLevel != 2 || ev.Level != 0 {
+		t.Fatalf("unexpected moved event %+v", ev)
+	}
+
+	// Lease 中的任务：只记录属性，供下一次 FeedBack 使用。
+	if err := s.Update(ctx, tok, levelAttrs(2)); err != nil {
+		t.Fatalf("update leased: %v", err)
+	}
+	if err := s.Move(ctx, tok, 1); err != ErrLeased {
+		t.Fatalf("expected ErrLeased got %v", err)
+	}
+	_ = s.FeedBack(ctx, tok, Feedback{RanFor: time.Millisecond})
+	if err := s.Update(ctx, 999, Attributes{}); err != ErrUnknownToken {
+		t.Fatalf("expected ErrUnknownToken got %v", err)
+	}
+}
+
+func TestScheduler_Move(t *testing.T) {
+	ctx := context.Background()
+	s, _ := NewDefault[string](3)
+	defer s.Close()
+
+	_, _ = s.Submit(ctx, "a", WithAttributes(levelAttrs(1)))
+	tok, _ := s.Submit(ctx, "b", WithAttributes(levelAttrs(1)))
+	if err := s.Move(ctx, tok, 3); err != ErrInvalidLevel {
+		t.Fatalf("expected ErrInvalidLevel got %v", err)
+	}
+	if err := s.Move(ctx, tok, 2); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if st := s.Stats(ctx); st.ByLevel[1] != 1 || st.ByLevel[2] != 1 {
+		t.Fatalf("unexpected by level %v", st.ByLevel)
+	}
+	// 同 level 内从中间移出后 FIFO 顺序不受影响。
+	_, _ = s.Submit(ctx, "c", WithAttributes(levelAttrs(1)))
+	for _, want := range []string{"a", "c", "b"} {
+		lease, _ := s.Next(ctx)
+		if lease.Task != want {
+			t.Fatalf("expected %s got %s", want, lease.Task)
+		}
+	}
+}
+
+func TestScheduler_UpdateWithoutUpdater(t *testing.T) {
+	ctx := context.Background()
+	// 只嵌入 Policy 接口：不实现 Updater
+	s, _ := New[string](struct{ Policy[string] }{NewDefaultPolicy[string](3, DefaultPolicyConfig{})})
+	defer s.Close()
+
+	tok, _ := s.Submit(ctx, "a", WithAttributes(levelAttrs(2)))
+	if err := s.Update(ctx, tok, levelAttrs(0)); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	lease, _ := s.Next(ctx)
+	if lease.Level != 2 {
+		t.Fatalf("expected level kept at 2 got %d", lease.Level)
+	}
+	// 新属性仍作为下一次 FeedBack 的默认 Attrs
+	if st := s.(*scheduler[string]).states[tok]; st.attrs != levelAttrs(0) {
+		t.Fatalf("expected attrs updated got %+v", st.attrs)
+	}
+}
+
+func TestPolicy_OnUpdate(t *testing.T) {
+	now := time.Unix(100, 0)
+	p := NewDefaultPolicy[int](3, DefaultPolicyConfig{})
+	// 任务因反馈被降到 level 2 后属性提升一档：仍保留一层降级。
+	if got := p.OnUpdate(now, 2, 0, levelAttrs(2), levelAttrs(1)); got != 1 {
+		t.Fatalf("expected level 1 got %d", got)
+	}
+	if got := p.OnUpdate(now, 0, 0, levelAttrs(2), levelAttrs(0)); got != 0 {
+		t.Fatalf("expected clamp to 0 got %d", got)
+	}
+
+	edf := NewEDFPolicy[int](5, EDFConfig{Granularity: time.Second})
+	if got := edf.OnUpdate(now, 4, 0, Attributes{}, Attributes{Deadline: now.Add(2500 * time.Millisecond)}); got != 2 {
+		t.Fatalf("expected edf level 2 got %d", got)
+	}
+	if got := edf.OnUpdate(now, 1, 0, Attributes{Deadline: now}, Attributes{}); got != 4 {
+		t.Fatalf("expected edf no-deadline level 4 got %d", got)
+	}
+}
@@ -0,0 +1,110 @@
+package mlfq
+
+import "github.com/arknights-w/go-utils/container/ringqueue"
+
+// spaceWaiter 是一个等待向 level 提交任务的 Submit（AdmitBlock）。
+type spaceWaiter struct {
+	ch    chan struct{}
+	level int
+}
+
+// waitList 是 AdmitBlock 下等待空位的 Submit 队列（FIFO，需在调度器锁内使用）。
+//
+// 每腾出一个空位只唤醒一个能用上它的等待者；被唤醒后仍没有空位的等待者回到队头，不丢失位置。
+type waitList struct {
+	q ringqueue.Queue[*spaceWaiter]
+}
+
+func (w *waitList) Len() int { return w.q.Len() }
+
+// add 登记一个等待者；retry 表示此前已被唤醒过，插回队头。
+func (w *waitList) add(level int, retry bool) *spaceWaiter {
+	sw := &spaceWaiter{ch: make(chan struct{}, 1), level: level}
+	if retry {
+		w.q.PushFront(sw)
+	} else {
+		w.q.PushBack(sw)
+	}
+	return sw
+}
+
+// remove 撤销等待者；若其已被唤醒（不在队列中）则返回 false。
+func (w *waitList) remove(sw *spaceWaiter) bool {
+	i := w.q.IndexFunc(func(v *spaceWaiter) bool { return v == sw })
+	if i < 0 {
+		return false
+	}
+	_, _ = w.q.RemoveAt(i)
+	return true
+}
+
+// wakeFirst 唤醒最早登记且 fits(level) 的等待者；没有这样的等待者时返回 false。
+func (w *waitList) wakeFirst(fits func(level int) bool) bool {
+	i := w.q.IndexFunc(func(v *spaceWaiter) bool { return fits(v.level) })
+	if i < 0 {
+		return false
+	}
+	sw, _ := w.q.RemoveAt(i)
+	sw.ch <- struct{}{}
+	return true
+}
+
+// wakeAll 唤醒全部等待者。
+func (w *waitList) wakeAll() {
+	for {
+		sw, ok := w.q.PopFront()
+		if !ok {
+			return
+		}
+		sw.ch <- struct{}{}
+	}
+}
+
+// leaseWaiter 是一个 NextWaitN 等待者：唤醒方在持锁时直接把 Lease 填入 leases 再关闭 done，
+// 任务因此不会被后来的调用方抢走；done 关闭而 leases 为空表示调度器已关闭。
+type leaseWaiter[T any] struct {
+	n      int
+	leases []Lease[T]
+	done   chan struct{}
+}
+
+// leaseWaitList 是 NextWaitN 等待者的 FIFO 队列（需在调度器锁内使用）。
+type leaseWaitList[T any] struct {
+	q ringqueue.Queue[*leaseWaiter[T]]
+}
+
+func (w *leaseWaitList[T]) Len() int { return w.q.Len() }
+
+// add 登记一个至多取 n 个 Lease 的等待者。
+func (w *leaseWaitList[T]) add(n int) *leaseWaiter[T] {
+	lw := &leaseWaiter[T]{n: n, done: make(chan struct{})}
+	w.q.PushBack(lw)
+	return lw
+}
+
+// remove 撤销等待者；若其已被唤醒（不在队列中）则返回 false。
+func (w *leaseWaitList[T]) remove(lw *leaseWaiter[T]) bool {
+	i := w.q.IndexFunc(func(v *leaseWaiter[T]) bool { return v == lw })
+	if i < 0 {
+		return false
+	}
+	_, _ = w.q.RemoveAt(i)
+	return true
+}
+
+// front 返回最早登记的等待者。
+func (w *leaseWaitList[T]) front() (*leaseWaiter[T], bool) { return w.q.PeekFront() }
+
+// wakeFront 移出并唤醒最早登记的等待者。
+func (w *leaseWaitList[T]) wakeFront() {
+	if lw, ok := w.q.PopFront(); ok {
+		close(lw.done)
+	}
+}
+
+// closeAll 唤醒全部等待者（不交付 Lease）。
+func (w *leaseWaitList[T]) closeAll() {
+	for w.q.Len() > 0 {
+		w.wakeFront()
+	}
+}
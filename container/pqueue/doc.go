@@ -0,0 +1,6 @@
+// Package pqueue 提供一个泛型的可索引优先队列（二叉堆）。
+//
+// 特性：
+//   - Push 返回 Handle，可通过 Handle 在 O(log n) 内 Update/Remove 任意元素
+//   - 通过比较函数决定顺序，NewMin/NewMax 提供有序类型的便捷构造
+package pqueue
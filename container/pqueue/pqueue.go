@@ -0,0 +1,182 @@
+package pqueue
+
+import "cmp"
+
+// Handle 指向队列中的一个元素，用于后续 Update/Remove。
+//
+// 元素被 Pop/Remove 后 Handle 失效（Index 返回 -1）。
+type Handle[T, P any] struct {
+	value T
+	prio  P
+	index int
+}
+
+// Value 返回元素值。
+func (h *Handle[T, P]) Value() T { return h.value }
+
+// Priority 返回元素当前优先级。
+func (h *Handle[T, P]) Priority() P { return h.prio }
+
+// Index 返回元素在堆中的下标；已出队时返回 -1。
+func (h *Handle[T, P]) Index() int { return h.index }
+
+// Queue 是一个基于二叉堆的优先队列：less(a, b) 为 true 表示 a 先于 b 出队。
+//
+// Push/Pop/Update/Remove 为 O(log n)，Peek 为 O(1)。非线程安全。
+type Queue[T, P any] struct {
+	less  func(a, b P) bool
+	items []*Handle[T, P]
+}
+
+// New 使用比较函数创建优先队列。
+func New[T, P any](less func(a, b P) bool) *Queue[T, P] {
+	if less == nil {
+		panic("pqueue: nil less func")
+	}
+	return &Queue[T, P]{less: less}
+}
+
+// NewMin 创建小顶堆：优先级越小越先出队。
+func NewMin[T any, P cmp.Ordered]() *Queue[T, P] {
+	return New[T](func(a, b P) bool { return a < b })
+}
+
+// NewMax 创建大顶堆：优先级越大越先出队。
+func NewMax[T any, P cmp.Ordered]() *Queue[T, P] {
+	return New[T](func(a, b P) bool { return a > b })
+}
+
+func (q *Queue[T, P]) Len() int { return len(q.items) }
+
+// Push 入队并返回 Handle。
+func (q *Queue[T, P]) Push(v T, prio P) *Handle[T, P] {
+	h := &Handle[T, P]{value: v, prio: prio, index: len(q.items)}
+	q.items = append(q.items, h)
+	q.up(h.index)
+	return h
+}
+
+// Peek 查看堆顶但不出队。
+func (q *Queue[T, P]) Peek() (T, P, bool) {
+	if len(q.items) == 0 {
+		var (
+			zv T
+			zp P
+		)
+		return zv, zp, false
+	}
+	h := q.items[0]
+	return h.value, h.prio, true
+}
+
+// PeekHandle 返回堆顶元素的 Handle；队列为空时返回 nil。
+func (q *Queue[T, P]) PeekHandle() *Handle[T, P] {
+	if len(q.items) == 0 {
+		return nil
+	}
+	return q.items[0]
+}
+
+// Pop 弹出堆顶元素。
+func (q *Queue[T, P]) Pop() (T, P, bool) {
+	if len(q.items) == 0 {
+		var (
+			zv T
+			zp P
+		)
+		return zv, zp, false
+	}
+	h := q.removeAt(0)
+	return h.value, h.prio, true
+}
+
+// Update 修改元素优先级并调整位置；Handle 已失效时返回 false。
+func (q *Queue[T, P]) Update(h *Handle[T, P], prio P) bool {
+	if !q.owns(h) {
+		return false
+	}
+	h.prio = prio
+	q.fix(h.index)
+	return true
+}
+
+// Remove 移除任意元素；Handle 已失效时返回 false。
+func (q *Queue[T, P]) Remove(h *Handle[T, P]) (T, bool) {
+	if !q.owns(h) {
+		var zero T
+		return zero, false
+	}
+	q.removeAt(h.index)
+	return h.value, true
+}
+
+// Reset 清空队列，所有 Handle 失效。
+func (q *Queue[T, P]) Reset() {
+	for i, h := range q.items {
+		h.index = -1
+		q.items[i] = nil
+	}
+	q.items = q.items[:0]
+}
+
+func (q *Queue[T, P]) owns(h *Handle[T, P]) bool {
+	return h != nil && h.index >= 0 && h.index < len(q.items) && q.items[h.index] == h
+}
+
+func (q *Queue[T, P]) removeAt(i int) *Handle[T, P] {
+	n := len(q.items) - 1
+	h := q.items[i]
+	if i != n {
+		q.swap(i, n)
+	}
+	q.items[n] = nil
+	q.items = q.items[:n]
+	if i != n {
+		q.fix(i)
+	}
+	h.index = -1
+	return h
+}
+
+func (q *Queue[T, P]) fix(i int) {
+	if !q.down(i) {
+		q.up(i)
+	}
+}
+
+func (q *Queue[T, P]) up(i int) {
+	for i > 0 {
+		p := (i - 1) / 2
+		if !q.less(q.items[i].prio, q.items[p].prio) {
+			break
+		}
+		q.swap(i, p)
+		i = p
+	}
+}
+
+func (q *Queue[T, P]) down(i0 int) bool {
+	i, n := i0, len(q.items)
+	for {
+		l := 2*i + 1
+		if l >= n {
+			break
+		}
+		j := l
+		if r := l + 1; r < n && q.less(q.items[r].prio, q.items[l].prio) {
+			j = r
+		}
+		if !q.less(q.items[j].prio, q.items[i].prio) {
+			break
+		}
+		q.swap(i, j)
+		i = j
+	}
+	return i > i0
+}
+
+func (q *Queue[T, P]) swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+	q.items[i].index = i
+	q.items[j].index = j
+}
@@ -0,0 +1,38 @@
+package pqueue
+
+import (
+	"strconv"
+	"testing"
+)
+
+func BenchmarkQueue_PushPop(b *testing.B) {
+	for _, n := range []int{64, 4096, 65536} {
+		b.Run("n="+strconv.Itoa(n), func(b *testing.B) {
+			q := NewMin[int, int]()
+			for i := range n {
+				q.Push(i, i)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				q.Push(i, i%n)
+				_, _, _ = q.Pop()
+			}
+		})
+	}
+}
+
+func BenchmarkQueue_Remove(b *testing.B) {
+	for _, n := range []int{64, 4096, 65536} {
+		b.Run("n="+strconv.Itoa(n), func(b *testing.B) {
+			q := NewMin[int, int]()
+			for i := range n {
+				q.Push(i, i)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				h := q.Push(i, i%n)
+				_, _ = q.Remove(h)
+			}
+		})
+	}
+}
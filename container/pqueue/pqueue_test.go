@@ -0,0 +1,101 @@
+package pqueue
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestQueue_MinOrder(t *testing.T) {
+	q := NewMin[string, int]()
+	for _, p := range []int{5, 1, 4, 2, 3} {
+		q.Push("v", p)
+	}
+	for want := 1; want <= 5; want++ {
+		_, p, ok := q.Pop()
+		if !ok || p != want {
+			t.Fatalf("expected %d got %d ok=%v", want, p, ok)
+		}
+	}
+	if _, _, ok := q.Pop(); ok {
+		t.Fatalf("expected empty")
+	}
+}
+
+func TestQueue_MaxOrder(t *testing.T) {
+	q := NewMax[int, int]()
+	for i := range 10 {
+		q.Push(i, i)
+	}
+	if v, _, _ := q.Peek(); v != 9 {
+		t.Fatalf("expected peek=9 got %d", v)
+	}
+}
+
+func TestQueue_UpdateRemove(t *testing.T) {
+	q := NewMin[string, int]()
+	a := q.Push("a", 1)
+	b := q.Push("b", 2)
+	c := q.Push("c", 3)
+
+	if !q.Update(c, 0) {
+		t.Fatalf("expected update ok")
+	}
+	if v, _, _ := q.Peek(); v != "c" {
+		t.Fatalf("expected c got %s", v)
+	}
+	if v, ok := q.Remove(a); !ok || v != "a" {
+		t.Fatalf("expected remove a got %s ok=%v", v, ok)
+	}
+	if _, ok := q.Remove(a); ok {
+		t.Fatalf("expected stale handle")
+	}
+	if q.Update(a, 5) {
+		t.Fatalf("expected stale handle update=false")
+	}
+
+	var got []string
+	for q.Len() > 0 {
+		v, _, _ := q.Pop()
+		got = append(got, v)
+	}
+	if !slices.Equal(got, []string{"c", "b"}) {
+		t.Fatalf("unexpected order %v", got)
+	}
+	if b.Index() != -1 {
+		t.Fatalf("expected popped handle invalid")
+	}
+}
+
+func TestQueue_RandomAgainstSort(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	q := NewMin[int, int]()
+	var hs []*Handle[int, int]
+	for i := range 1000 {
+		hs = append(hs, q.Push(i, r.Intn(10000)))
+	}
+	// 随机更新/删除一部分
+	alive := map[*Handle[int, int]]bool{}
+	for _, h := range hs {
+		alive[h] = true
+	}
+	for _, h := range hs[:300] {
+		if r.Intn(2) == 0 {
+			q.Update(h, r.Intn(10000))
+		} else {
+			q.Remove(h)
+			delete(alive, h)
+		}
+	}
+	var want []int
+	for h := range alive {
+		want = append(want, h.Priority())
+	}
+	slices.Sort(want)
+	for i, w := range want {
+		_, p, ok := q.Pop()
+		if !ok || p != w {
+			t.Fatalf("idx=%d expected %d got %d", i, w, p)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package ringqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrClosed 表示 BlockingQueue 已 Close。
+var ErrClosed = errors.New("ringqueue: closed")
+
+// BlockingQueue 是一个有界、线程安全的阻塞队列（MPMC），语义上类似带缓冲的 channel。
+//
+// 底层存储复用 Queue，并用 mutex + 两个条件变量（notEmpty/notFull）实现阻塞等待；
+// 相比 channel 额外提供 Len/Peek/DrainTo/TryPush 等操作。
+//
+// 关闭语义：
+//   - Close 后所有 Push 返回 ErrClosed
+//   - Close 后 Pop 仍可取出剩余元素，队列取空后返回 ErrClosed
+type BlockingQueue[T any] struct {
+	mu       sync.Mutex
+	notEmpty sync.Cond
+	notFull  sync.Cond
+
+	q      Queue[T]
+	cap    int
+	closed bool
+}
+
+// NewBlocking 创建一个容量为 capacity 的阻塞队列；capacity<=0 时按 1 处理。
+func NewBlocking[T any](capacity int) *BlockingQueue[T] {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	b := &BlockingQueue[T]{cap: capacity}
+	b.notEmpty.L = &b.mu
+	b.notFull.L = &b.mu
+	return b
+}
+
+func (b *BlockingQueue[T]) Cap() int { return b.cap }
+
+func (b *BlockingQueue[T]) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.q.Len()
+}
+
+// Closed 返回队列是否已关闭。
+func (b *BlockingQueue[T]) Closed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+// Push 入队；队列满时阻塞，直到有空位或队列关闭。
+func (b *BlockingQueue[T]) Push(v T) error {
+	return b.PushCtx(context.Background(), v)
+}
+
+// TryPush 非阻塞入队；队列满或已关闭时返回 false。
+func (b *BlockingQueue[T]) TryPush(v T) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed || b.q.Len() >= b.cap {
+		return false
+	}
+	b.q.PushBack(v)
+	b.notEmpty.Signal()
+	return true
+}
+
+// PushCtx 入队；队列满时阻塞，直到有空位、队列关闭（ErrClosed）或 ctx 结束（ctx.Err()）。
+func (b *BlockingQueue[T]) PushCtx(ctx context.Context, v T) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.waitLocked(ctx, &b.notFull, func() bool { return b.closed || b.q.Len() < b.cap }); err != nil {
+		return err
+	}
+	if b.closed {
+		return ErrClosed
+	}
+	b.q.PushBack(v)
+	b.notEmpty.Signal()
+	return nil
+}
+
+// Pop 出队；队列空时阻塞，直到有元素或队列关闭。
+//
+// 队列关闭且已取空时返回 ok=false。
+func (b *BlockingQueue[T]) Pop() (T, bool) {
+	v, err := b.PopCtx(context.Background())
+	return v, err == nil
+}
+
+// TryPop 非阻塞出队；队列空时返回 false。
+func (b *BlockingQueue[T]) TryPop() (T, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	v, ok := b.q.PopFront()
+	if ok {
+		b.notFull.Signal()
+	}
+	return v, ok
+}
+
+// PopCtx 出队；队列空时阻塞，直到有元素、队列关闭且取空（ErrClosed）或 ctx 结束（ctx.Err()）。
+func (b *BlockingQueue[T]) PopCtx(ctx context.Context) (T, error) {
+	var zero T
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.waitLocked(ctx, &b.notEmpty, func() bool { return b.closed || b.q.Len() > 0 }); err != nil {
+		return zero, err
+	}
+	v, ok := b.q.PopFront()
+	if !ok {
+		return zero, ErrClosed
+	}
+	b.notFull.Signal()
+	return v, nil
+}
+
+// Peek 查看队头但不出队。
+func (b *BlockingQueue[T]) Peek() (T, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.q.PeekFront()
+}
+
+// DrainTo 非阻塞地取出至多 limit 个元素并追加到 dst 后返回；limit<=0 表示取出全部。
+func (b *BlockingQueue[T]) DrainTo(dst []T, limit int) []T {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := b.q.Len()
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	for range n {
+		v, _ := b.q.PopFront()
+		dst = append(dst, v)
+	}
+	if n > 0 {
+		b.notFull.Broadcast()
+	}
+	return dst
+}
+
+// Close 关闭队列并唤醒所有等待者。
+//
+// Close 幂等：允许重复调用。
+func (b *BlockingQueue[T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	b.notEmpty.Broadcast()
+	b.notFull.Broadcast()
+}
+
+// waitLocked 在持锁状态下等待 ready 成立；ctx 结束时返回 ctx.Err()。
+//
+// sync.Cond 本身不感知 ctx，这里借助 context.AfterFunc 在 ctx 结束时广播唤醒。
+func (b *BlockingQueue[T]) waitLocked(ctx context.Context, cond *sync.Cond, ready func() bool) error {
+	if ready() {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		cond.Broadcast()
+	})
+	defer stop()
+	// 被唤醒后优先检查 ready：条件满足时即使 ctx 已结束也继续执行，避免吞掉 Signal。
+	for !ready() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		cond.Wait()
+	}
+	return nil
+}
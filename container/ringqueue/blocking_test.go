@@ -0,0 +1,110 @@
+package ringqueue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBlockingQueue_TryPushFull(t *testing.T) {
+	q := NewBlocking[int](2)
+	if !q.TryPush(1) || !q.TryPush(2) {
+		t.Fatalf("expected push ok")
+	}
+	if q.TryPush(3) {
+		t.Fatalf("expected full")
+	}
+	if v, ok := q.Peek(); !ok || v != 1 {
+		t.Fatalf("expected peek=1 got %d ok=%v", v, ok)
+	}
+	if q.Len() != 2 {
+		t.Fatalf("expected len=2 got %d", q.Len())
+	}
+}
+
+func TestBlockingQueue_PushCtxTimeout(t *testing.T) {
+	q := NewBlocking[int](1)
+	_ = q.Push(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := q.PushCtx(ctx, 2); err != context.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded got %v", err)
+	}
+}
+
+func TestBlockingQueue_PopCtxWakeup(t *testing.T) {
+	q := NewBlocking[int](1)
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		_ = q.Push(42)
+	}()
+	v, err := q.PopCtx(context.Background())
+	if err != nil || v != 42 {
+		t.Fatalf("expected 42 got %d err=%v", v, err)
+	}
+}
+
+func TestBlockingQueue_CloseDrainsRemaining(t *testing.T) {
+	q := NewBlocking[int](4)
+	_ = q.Push(1)
+	_ = q.Push(2)
+	q.Close()
+	if err := q.Push(3); err != ErrClosed {
+		t.Fatalf("expected ErrClosed got %v", err)
+	}
+	got := q.DrainTo(nil, 1)
+	if len(got) != 1 || got[0] != 1 {
+		t.Fatalf("expected [1] got %v", got)
+	}
+	if v, ok := q.Pop(); !ok || v != 2 {
+		t.Fatalf("expected 2 got %d ok=%v", v, ok)
+	}
+	if _, err := q.PopCtx(context.Background()); err != ErrClosed {
+		t.Fatalf("expected ErrClosed got %v", err)
+	}
+}
+
+func TestBlockingQueue_MPMC(t *testing.T) {
+	const producers, perProducer = 4, 1000
+	q := NewBlocking[int](8)
+
+	var wg sync.WaitGroup
+	for p := range producers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perProducer {
+				_ = q.Push(p*perProducer + i)
+			}
+		}()
+	}
+
+	seen := make([]bool, producers*perProducer)
+	var mu sync.Mutex
+	var cwg sync.WaitGroup
+	for range 4 {
+		cwg.Add(1)
+		go func() {
+			defer cwg.Done()
+			for {
+				v, ok := q.Pop()
+				if !ok {
+					return
+				}
+				mu.Lock()
+				seen[v] = true
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	q.Close()
+	cwg.Wait()
+	for i, ok := range seen {
+		if !ok {
+			t.Fatalf("missing %d", i)
+		}
+	}
+}
@@ -1,6 +1,7 @@
-// Package ringqueue 提供一个基于环形数组的 FIFO 队列实现。
+// Package ringqueue 提供一个基于环形数组的 FIFO 队列实现，以及在其之上的有界阻塞队列 BlockingQueue。
 //
 // 特性：
 //   - size < cap 时不扩容，通过 (head+size)%cap 追加
 //   - 满时按 2 倍扩容，并保持逻辑顺序搬移一次数据
+//   - BlockingQueue 为有界 MPMC 队列（mutex + cond），支持 ctx 取消与 Close 语义
 package ringqueue
@@ -34,6 +34,19 @@ func (q *Queue[T]) PushBack(v T) {
 	q.size++
 }
 
+// PushFront 插入到队头。
+func (q *Queue[T]) PushFront(v T) {
+	if cap(q.buf) == 0 {
+		q.buf = make([]T, 4)
+	}
+	if q.size == cap(q.buf) {
+		q.grow()
+	}
+	q.head = (q.head - 1 + cap(q.buf)) % cap(q.buf)
+	q.buf[q.head] = v
+	q.size++
+}
+
 // PeekFront 查看队头但不出队。
 func (q *Queue[T]) PeekFront() (T, bool) {
 	if q.size == 0 {
@@ -63,6 +76,63 @@ func (q *Queue[T]) PopFront() (T, bool) {
 	return v, true
 }
 
+// At 返回从队头起第 i 个元素（0 为队头）。
+func (q *Queue[T]) At(i int) (T, bool) {
+	if i < 0 || i >= q.size {
+		var zero T
+		return zero, false
+	}
+	return q.buf[(q.head+i)%cap(q.buf)], true
+}
+
+// Set 替换从队头起第 i 个元素；i 越界时返回 false。
+func (q *Queue[T]) Set(i int, v T) bool {
+	if i < 0 || i >= q.size {
+		return false
+	}
+	q.buf[(q.head+i)%cap(q.buf)] = v
+	return true
+}
+
+// IndexFunc 返回从队头起第一个满足 match 的元素下标；不存在时返回 -1。
+func (q *Queue[T]) IndexFunc(match func(T) bool) int {
+	for i := 0; i < q.size; i++ {
+		if match(q.buf[(q.head+i)%cap(q.buf)]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// RemoveAt 移除从队头起第 i 个元素，并保持其余元素的相对顺序。
+//
+// 复杂度为 O(min(i, size-i))：靠近队头时整体后移前半段，否则前移后半段。
+func (q *Queue[T]) RemoveAt(i int) (T, bool) {
+	var zero T
+	if i < 0 || i >= q.size {
+		return zero, false
+	}
+	c := cap(q.buf)
+	v := q.buf[(q.head+i)%c]
+	if i < q.size/2 {
+		for j := i; j > 0; j-- {
+			q.buf[(q.head+j)%c] = q.buf[(q.head+j-1)%c]
+		}
+		q.buf[q.head] = zero
+		q.head = (q.head + 1) % c
+	} else {
+		for j := i; j < q.size-1; j++ {
+			q.buf[(q.head+j)%c] = q.buf[(q.head+j+1)%c]
+		}
+		q.buf[(q.head+q.size-1)%c] = zero
+	}
+	q.size--
+	if q.size == 0 {
+		q.head = 0
+	}
+	return v, true
+}
+
 func (q *Queue[T]) grow() {
 	nb := make([]T, cap(q.buf)*2)
 	for i := range q.size {
@@ -20,3 +20,13 @@ func BenchmarkQueue_PushPop(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkBlockingQueue_Parallel(b *testing.B) {
+	q := NewBlocking[int](1024)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = q.Push(1)
+			_, _ = q.Pop()
+		}
+	})
+}
@@ -42,3 +42,55 @@ func TestQueue_WrapAndGrowKeepsOrder(t *testing.T) {
 		}
 	}
 }
+
+func TestQueue_RemoveAtKeepsOrder(t *testing.T) {
+	for _, idx := range []int{0, 1, 5, 8, 9} {
+		var q Queue[int]
+		// 先制造回绕
+		for i := range 6 {
+			q.PushBack(-1 - i)
+		}
+		for range 6 {
+			_, _ = q.PopFront()
+		}
+		for i := range 10 {
+			q.PushBack(i)
+		}
+		if i := q.IndexFunc(func(v int) bool { return v == idx }); i != idx {
+			t.Fatalf("expected index %d got %d", idx, i)
+		}
+		v, ok := q.RemoveAt(idx)
+		if !ok || v != idx {
+			t.Fatalf("expected removed %d got %d ok=%v", idx, v, ok)
+		}
+		for i := range 10 {
+			if i == idx {
+				continue
+			}
+			v, ok := q.PopFront()
+			if !ok || v != i {
+				t.Fatalf("remove=%d expected %d got %d ok=%v", idx, i, v, ok)
+			}
+		}
+		if q.Len() != 0 {
+			t.Fatalf("expected empty")
+		}
+	}
+}
+
+func TestQueue_PushFront(t *testing.T) {
+	var q Queue[int]
+	for i := range 5 {
+		q.PushBack(i)
+	}
+	for i := range 5 {
+		q.PushFront(-1 - i)
+	}
+	for i := range 10 {
+		want := i - 5
+		v, ok := q.PopFront()
+		if !ok || v != want {
+			t.Fatalf("expected %d got %d ok=%v", want, v, ok)
+		}
+	}
+}
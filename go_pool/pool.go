@@ -75,11 +75,15 @@ func (s *Pool) Group() *TaskGroup {
 }
 
 func (s *Pool) Close() {
+	// 持锁关闭：demon 的调度会增删 workers
+	s.mu.Lock()
 	close(s.cancel)
-	for _, worker := range s.workers {
+	workers := s.workers
+	s.mu.Unlock()
+	for _, worker := range workers {
 		worker.Close()
 	}
-	for _, worker := range s.workers {
+	for _, worker := range workers {
 		worker.Wait()
 	}
 }
@@ -88,10 +92,17 @@ func (s *Pool) Close() {
 
 func (s *Pool) demon() {
 	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-ticker.C:
 			s.mu.Lock()
+			select {
+			case <-s.cancel:
+				s.mu.Unlock()
+				return
+			default:
+			}
 			s.schedule()
 			s.mu.Unlock()
 		case <-s.cancel:
@@ -1,10 +1,12 @@
 package go_pool
 
+import "sync/atomic"
+
 type Worker[id comparable] struct {
 	id     id
 	recv   chan Task
 	cancel chan struct{}
-	status workerStatus
+	status atomic.Uint32 // workerStatus，demon 与 Count 并发读写
 }
 
 func NewWorker[id comparable](_id id, chanSize ...int) *Worker[id] {
@@ -19,7 +21,7 @@ func NewWorker[id comparable](_id id, chanSize ...int) *Worker[id] {
 }
 
 func (w *Worker[id]) demon() {
-	w.status = WORKER_STATUS_PENDING
+	w.status.Store(uint32(WORKER_STATUS_PENDING))
 	for task := range w.recv {
 		if task == nil {
 			continue
@@ -27,17 +29,17 @@ func (w *Worker[id]) demon() {
 		w.safeRun(task)
 	}
 	close(w.cancel)
-	w.status = WORKER_STATUS_STOPPED
+	w.status.Store(uint32(WORKER_STATUS_STOPPED))
 }
 
 func (w *Worker[id]) safeRun(task Task) {
 	defer func() {
-		w.status = WORKER_STATUS_PENDING
+		w.status.Store(uint32(WORKER_STATUS_PENDING))
 		if r := recover(); r != nil {
 			// Handle panic
 		}
 	}()
-	w.status = WORKER_STATUS_RUNNING
+	w.status.Store(uint32(WORKER_STATUS_RUNNING))
 	task()
 }
 
@@ -70,7 +72,7 @@ func (w *Worker[id]) TryPopTask() (Task, bool) {
 
 func (w *Worker[id]) Count() int {
 	len := len(w.recv)
-	if w.status == WORKER_STATUS_RUNNING {
+	if workerStatus(w.status.Load()) == WORKER_STATUS_RUNNING {
 		len += 1
 	}
 	return len
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"sync"
+	"time"
 
 	"github.com/arknights-w/go-utils/rely/timewheel"
 )
@@ -12,15 +13,28 @@ type Cache struct {
 }
 
 type data struct {
-	id  int64
+	tm  *timewheel.Timer
 	val any
 }
 
-func NewCache() *Cache {
-	return &Cache{
-		imap: &sync.Map{},
-		tw:   timewheel.NewTimeWheel(),
+type Option func(*Cache)
+
+// WithTimeWheel 使用指定的时间轮处理过期（例如测试中使用 timewheel.FakeClock 驱动的时间轮）。
+func WithTimeWheel(tw timewheel.TimeWheel) Option {
+	return func(c *Cache) {
+		c.tw = tw
+	}
+}
+
+func NewCache(opts ...Option) *Cache {
+	c := &Cache{imap: &sync.Map{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.tw == nil {
+		c.tw = timewheel.NewTimeWheel()
 	}
+	return c
 }
 
 func (c *Cache) Set(key, val any) {
@@ -33,12 +47,12 @@ func (c *Cache) SetExpireWithFn(key, val any, expire int64, execFn func()) {
 	old, ok := c.imap.LoadOrStore(key, &data{val: val})
 	data := old.(*data)
 	if ok {
-		if data.id > 0 {
-			c.tw.RemoveTask(data.id)
+		if data.tm != nil {
+			data.tm.Stop()
 		}
 		data.val = val
 	}
-	data.id, _ = c.tw.AddDelayedTask(expire, execFn)
+	data.tm, _ = c.tw.AddDelayedTask(time.Duration(expire)*time.Second, timewheel.Func(execFn))
 }
 
 func (c *Cache) SetExpire(key, val any, expire int64) {
@@ -51,12 +65,12 @@ func (c *Cache) SetExpireAtWithFn(key, val any, execTime int64, execFn func()) {
 	old, ok := c.imap.LoadOrStore(key, &data{val: val})
 	data := old.(*data)
 	if ok {
-		if data.id > 0 {
-			c.tw.RemoveTask(data.id)
+		if data.tm != nil {
+			data.tm.Stop()
 		}
 		data.val = val
 	}
-	data.id, _ = c.tw.AddScheduledTask(execTime, execFn)
+	data.tm, _ = c.tw.AddScheduledTask(time.Unix(execTime, 0), timewheel.Func(execFn))
 }
 
 func (c *Cache) SetExpireAt(key, val any, execTime int64) {
@@ -68,16 +82,16 @@ func (c *Cache) SetExpireAt(key, val any, execTime int64) {
 func (c *Cache) Del(key any) {
 	if old, ok := c.imap.LoadAndDelete(key); ok {
 		data := old.(*data)
-		if data.id > 0 {
-			c.tw.RemoveTask(data.id)
+		if data.tm != nil {
+			data.tm.Stop()
 		}
 	}
 }
 
 func (c *Cache) Clear() {
 	c.imap.Range(func(key, value any) bool {
-		if data, ok := value.(*data); ok && data.id > 0 {
-			c.tw.RemoveTask(data.id)
+		if data, ok := value.(*data); ok && data.tm != nil {
+			data.tm.Stop()
 		}
 		return true
 	})
@@ -9,6 +9,7 @@ import (
 	"unsafe"
 
 	"github.com/arknights-w/go-utils/rely/cache"
+	"github.com/arknights-w/go-utils/rely/timewheel"
 )
 
 func TestXxx(t *testing.T) {
@@ -22,14 +23,26 @@ func TestXxx(t *testing.T) {
 }
 
 func TestXxx2(t *testing.T) {
-	cache := cache.NewCache()
+	clock := timewheel.NewFakeClock(time.Unix(0, 0))
+	tw := timewheel.NewTimeWheel(timewheel.WithClock(clock))
+	defer tw.Close()
+	cache := cache.NewCache(cache.WithTimeWheel(tw))
 	cache.Set("key", "value")
 	fmt.Printf("key: %v\n", cache.GetStringX("key"))
 
 	cache.SetExpire("key1", 1, 1)
 	fmt.Printf("key1: %v\n", cache.GetIntX("key1"))
-	time.Sleep(1003 * time.Millisecond)
-	fmt.Printf("key1: %v\n", cache.GetIntX("key1"))
+	clock.Advance(999 * time.Millisecond)
+	if cache.GetIntX("key1") != 1 {
+		t.Fatalf("key1 expired early")
+	}
+	clock.Advance(10 * time.Millisecond)
+	if _, ok := cache.Get("key1"); ok {
+		t.Fatalf("key1 should have expired")
+	}
+	if cache.GetStringX("key") != "value" {
+		t.Fatalf("key without expiry should be kept")
+	}
 }
 
 func TestParallelCache(t *testing.T) {
@@ -0,0 +1,111 @@
+package timewheel
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock 是时间轮使用的时钟，可通过 WithClock 替换（例如测试中使用 FakeClock）。
+type Clock interface {
+	Now() time.Time
+	// Tick 每隔 d 调用一次 fn(now)，直到返回的 stop 被调用；stop 返回后 fn 不会再被调用。
+	Tick(d time.Duration, fn func(now time.Time)) (stop func())
+}
+
+// WithClock 设置时间轮的时钟，默认使用系统时钟。
+//
+// 使用 *FakeClock 时到期任务在 FakeClock.Advance 中同步执行，而不是交给协程池。
+func WithClock(clock Clock) Option {
+	return func(c *config) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) Tick(d time.Duration, fn func(now time.Time)) func() {
+	tk := time.NewTicker(d)
+	cancel, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(done)
+		defer tk.Stop()
+		for {
+			select {
+			case <-cancel:
+				return
+			case now := <-tk.C:
+				fn(now)
+			}
+		}
+	}()
+	return func() {
+		close(cancel)
+		<-done
+	}
+}
+
+// FakeClock 是手动推进的时钟：Advance 同步地依次触发期间到期的每一次 Tick 回调。
+type FakeClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	id   int
+	subs map[int]*fakeTick
+}
+
+type fakeTick struct {
+	every time.Duration
+	next  time.Time
+	fn    func(now time.Time)
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now, subs: make(map[int]*fakeTick)}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) Tick(d time.Duration, fn func(now time.Time)) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.id++
+	id := c.id
+	c.subs[id] = &fakeTick{every: d, next: c.now.Add(d), fn: fn}
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.subs, id)
+	}
+}
+
+// Advance 将时钟推进 d，并按时间顺序同步调用期间到期的 Tick 回调（回调中可以调用 Now/Advance 以外的方法）。
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	target := c.now.Add(d)
+	for {
+		var due *fakeTick
+		for _, sub := range c.subs {
+			if !sub.next.After(target) && (due == nil || sub.next.Before(due.next)) {
+				due = sub
+			}
+		}
+		if due == nil {
+			break
+		}
+		now := due.next
+		c.now = now
+		due.next = now.Add(due.every)
+		c.mu.Unlock()
+		due.fn(now)
+		c.mu.Lock()
+	}
+	c.now = target
+	c.mu.Unlock()
+}
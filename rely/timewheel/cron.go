@@ -0,0 +1,234 @@
+package timewheel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule 是解析后的 cron 表达式：每个字段用位图表示允许的取值。
+type cronSchedule struct {
+	second, minute, hour, dom, month, dow uint64
+	// domStar/dowStar 表示日/星期字段不受限（* 或 ?）：两者都受限时按“或”匹配，否则按“与”匹配。
+	domStar, dowStar bool
+	loc              *time.Location
+}
+
+type cronField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronSecond = cronField{0, 59, nil}
+	cronMinute = cronField{0, 59, nil}
+	cronHour   = cronField{0, 23, nil}
+	cronDom    = cronField{1, 31, nil}
+	cronMonth  = cronField{1, 12, map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 星期 0 与 7 都表示周日。
+	cronDow = cronField{0, 7, map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// parseCron 解析 cron 表达式：
+//   - 5 个字段（分 时 日 月 星期）或 6 个字段（秒 分 时 日 月 星期）
+//   - 支持 *、?、逗号列表、a-b 范围、/step 步长、月份与星期的英文缩写，以及 @daily 等描述符
+//   - 可用 CRON_TZ=Asia/Shanghai（或 TZ=）前缀指定时区，默认使用 loc
+func parseCron(spec string, loc *time.Location) (*cronSchedule, error) {
+	s := strings.TrimSpace(spec)
+	if strings.HasPrefix(s, "CRON_TZ=") || strings.HasPrefix(s, "TZ=") {
+		tz, rest, _ := strings.Cut(s, " ")
+		_, name, _ := strings.Cut(tz, "=")
+		l, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron spec %q: %w", spec, err)
+		}
+		loc, s = l, strings.TrimSpace(rest)
+	}
+	if d, ok := cronDescriptors[s]; ok {
+		s = d
+	}
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("invalid cron spec %q: expected 5 or 6 fields, got %d", spec, len(fields))
+	}
+
+	c := &cronSchedule{loc: loc}
+	var err error
+	parse := func(i int, f cronField) uint64 {
+		if err != nil {
+			return 0
+		}
+		var bits uint64
+		bits, err = f.parse(fields[i])
+		if err != nil {
+			err = fmt.Errorf("invalid cron spec %q: %w", spec, err)
+		}
+		return bits
+	}
+	c.second = parse(0, cronSecond)
+	c.minute = parse(1, cronMinute)
+	c.hour = parse(2, cronHour)
+	c.dom = parse(3, cronDom)
+	c.month = parse(4, cronMonth)
+	c.dow = parse(5, cronDow)
+	if err != nil {
+		return nil, err
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow = c.dow&^(1<<7) | 1
+	}
+	c.domStar = fields[3] == "*" || fields[3] == "?"
+	c.dowStar = fields[5] == "*" || fields[5] == "?"
+	return c, nil
+}
+
+func (f cronField) parse(expr string) (uint64, error) {
+	var bits uint64
+	for part := range strings.SplitSeq(expr, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+			step = n
+		}
+		lo, hi := f.min, f.max
+		if rng != "*" && rng != "?" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = f.value(a); err != nil {
+				return 0, err
+			}
+			switch {
+			case isRange:
+				if hi, err = f.value(b); err != nil {
+					return 0, err
+				}
+			case !hasStep:
+				hi = lo
+			}
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("invalid range %q", part)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %q out of range [%d, %d]", s, f.min, f.max)
+	}
+	return v, nil
+}
+
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// next 返回严格晚于 t 的下一个触发时间；5 年内没有匹配时返回零值。
+func (c *cronSchedule) next(t time.Time) time.Time {
+	origLoc := t.Location()
+	t = t.In(c.loc).Add(time.Second - time.Duration(t.Nanosecond()))
+	yearLimit := t.Year() + 5
+	// added 表示已经跳过了某个字段，更低的字段从其最小值开始。
+	added := false
+
+wrap:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+	for c.month&(1<<uint(t.Month())) == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, c.loc)
+		}
+		t = t.AddDate(0, 1, 0)
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+	for !c.dayMatches(t) {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.loc)
+		}
+		t = t.AddDate(0, 0, 1)
+		// 夏令时切换可能使午夜不存在，修正到当天 0 点附近。
+		if h := t.Hour(); h != 0 {
+			if h > 12 {
+				t = t.Add(time.Duration(24-h) * time.Hour)
+			} else {
+				t = t.Add(time.Duration(-h) * time.Hour)
+			}
+		}
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+	for c.hour&(1<<uint(t.Hour())) == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, c.loc)
+		}
+		t = t.Add(time.Hour)
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+	for c.minute&(1<<uint(t.Minute())) == 0 {
+		if !added {
+			added = true
+			t = t.Truncate(time.Minute)
+		}
+		t = t.Add(time.Minute)
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+	for c.second&(1<<uint(t.Second())) == 0 {
+		if !added {
+			added = true
+			t = t.Truncate(time.Second)
+		}
+		t = t.Add(time.Second)
+		if t.Second() == 0 {
+			goto wrap
+		}
+	}
+	return t.In(origLoc)
+}
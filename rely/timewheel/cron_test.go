@@ -0,0 +1,47 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCron_Next(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	base := time.Date(2024, 2, 28, 23, 59, 30, 500, time.UTC)
+	cases := []struct {
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"* * * * *", base, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"*/15 * * * * *", base, time.Date(2024, 2, 28, 23, 59, 45, 0, time.UTC)},
+		{"0 12 * * mon-fri", base, time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)},
+		{"30 9 29 feb *", base, time.Date(2024, 2, 29, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", base, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		// 日与星期都受限时按“或”匹配：3 月 1 日是周五，3 月 3 日是周日。
+		{"0 0 3 * 7", base, time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", base, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", base, time.Time{}},
+		// 上海 0 点即 UTC 前一天 16 点。
+		{"CRON_TZ=Asia/Shanghai 0 0 * * *", base, time.Date(2024, 2, 29, 16, 0, 0, 0, time.UTC)},
+		{"TZ=Asia/Shanghai 0 0 * * *", base.In(shanghai), time.Date(2024, 2, 29, 16, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		s, err := parseCron(c.spec, time.UTC)
+		if err != nil {
+			t.Fatalf("%q: %v", c.spec, err)
+		}
+		if got := s.next(c.from); !got.Equal(c.want) {
+			t.Fatalf("%q: expected %v got %v", c.spec, c.want, got)
+		}
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "CRON_TZ=Nowhere/City * * * * *"} {
+		if _, err := parseCron(spec, time.UTC); err == nil {
+			t.Fatalf("%q: expected error", spec)
+		}
+	}
+}
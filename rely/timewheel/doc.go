@@ -0,0 +1,13 @@
+// Package timewheel 提供分层哈希时间轮（Varghese & Lauck，Linux/Kafka 同构）：
+//
+//   - 精度由 WithTick 配置（默认 10ms），每层 WithWheelSize 个槽、共 WithLevels 层
+//   - 添加与删除任务为 O(1)，推进每个 tick 摊还 O(1)
+//   - 任务为 func(ctx) error：ctx 在 Stop/RemoveTask/Close 时取消，错误与 panic 交给 WithOnError；
+//     默认每个任务在独立的 goroutine 中执行，可用 WithPool 指定协程池或 WithInline 在 tick 中同步执行
+//   - 后台 goroutine 被阻塞后会一次性追赶到当前时间，任务只会延后、不会提前执行
+//   - AddPeriodicTask 支持 FixedRate/FixedDelay，AddCronTask 支持 5/6 字段 cron 表达式与 CRON_TZ 时区；
+//     阻塞后错过的触发按 MissedFire（FireOnce/FireAll/Skip）处理
+//   - WithClock 可替换时钟；测试中使用 FakeClock，Advance 会同步执行期间到期的任务
+//
+// 与旧的堆实现的对比见 wheel_bench_test.go。
+package timewheel
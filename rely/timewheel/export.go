@@ -1,10 +1,9 @@
 package timewheel
 
 import (
+	"context"
 	"fmt"
 	"time"
-
-	"github.com/arknights-w/go-utils/go_pool"
 )
 
 var (
@@ -12,21 +11,92 @@ var (
 )
 
 type TimeWheel interface {
-	AddDelayedTask(delay int64, fn func()) (int64, error)
-	AddScheduledTask(execTime int64, fn func()) (int64, error)
+	// AddDelayedTask 在 delay 之后执行 fn，返回可 Stop/Reset 的任务句柄。
+	AddDelayedTask(delay time.Duration, fn Task) (*Timer, error)
+	// AddScheduledTask 在 execTime 执行 fn（已过去的时间在下一个 tick 执行），返回任务句柄。
+	AddScheduledTask(execTime time.Time, fn Task) (*Timer, error)
+	// AddPeriodicTask 每隔 interval 执行一次 fn，模式与错过触发的处理见 PeriodicOption。
+	AddPeriodicTask(interval time.Duration, fn Task, opts ...PeriodicOption) (*Timer, error)
+	// AddCronTask 按 5/6 字段的 cron 表达式执行 fn，支持 CRON_TZ= 前缀指定时区。
+	AddCronTask(spec string, fn Task, opts ...PeriodicOption) (*Timer, error)
+	// RemoveTask 按 id 取消任务并取消其正在执行的 ctx，等价于 Timer.Stop。
 	RemoveTask(id int64)
 
+	// Close 停止时间轮并取消所有正在执行的任务的 ctx。
 	Close() error
 }
 
-func NewTimeWheel() TimeWheel {
+type config struct {
+	clock   Clock
+	tick    time.Duration
+	bits    uint
+	levels  int
+	pool    Pool
+	inline  bool
+	onError func(id int64, err error)
+}
+
+type Option func(*config)
+
+// WithTick 设置时间轮的精度（一个槽的时长），默认 10ms。任务最多晚一个 tick 执行。
+func WithTick(tick time.Duration) Option {
+	return func(c *config) {
+		if tick > 0 {
+			c.tick = tick
+		}
+	}
+}
+
+// WithWheelSize 设置每层的槽数，向上取整为 2 的幂，默认 64。
+func WithWheelSize(size int) Option {
+	return func(c *config) {
+		if size > 1 {
+			c.bits = 0
+			for 1<<c.bits < size {
+				c.bits++
+			}
+		}
+	}
+}
+
+// WithLevels 设置时间轮层数，默认 4（10ms*64^4 约 19 天，更远的任务会在最高层多转几圈）。
+func WithLevels(levels int) Option {
+	return func(c *config) {
+		if levels > 0 {
+			c.levels = levels
+		}
+	}
+}
+
+// NewTimeWheel 创建一个分层时间轮，时钟每个 tick 推进一次。
+//
+// 到期任务默认各自在新的 goroutine 中执行，可用 WithPool/WithInline 替换；使用 FakeClock 且未指定 WithPool 时任务同步执行。
+func NewTimeWheel(opts ...Option) TimeWheel {
+	cfg := config{clock: realClock{}, tick: 10 * time.Millisecond, bits: 6, levels: 4}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	// 保证 max tick 距离不溢出 int64
+	cfg.levels = min(cfg.levels, 62/int(cfg.bits))
+
+	t := newTicker(cfg)
+	t.stop = cfg.clock.Tick(cfg.tick, t.onTick)
+	return t
+}
+
+func newTicker(cfg config) *ticker {
+	if _, fake := cfg.clock.(*FakeClock); fake && cfg.pool == nil {
+		cfg.inline = true
+	}
+	ctx, cancel := context.WithCancel(context.Background())
 	t := &ticker{
-		start:  time.Now().Unix(),
-		now:    time.Now().Unix(),
-		pool:   go_pool.NewPool(5, 1),
-		mgr:    &taskMgr{},
-		cancel: make(chan struct{}),
+		cfg:       cfg,
+		start:     cfg.clock.Now(),
+		wheel:     newWheel(cfg.bits, cfg.levels, 0),
+		timers:    make(map[int64]*Timer),
+		running:   make(map[int64]*Timer),
+		ctx:       ctx,
+		cancelAll: cancel,
 	}
-	go t.demon(1) // 1 second time wheel
 	return t
 }
@@ -0,0 +1,70 @@
+package timewheel
+
+import (
+	"sync"
+
+	"github.com/arknights-w/go-utils/container/pqueue"
+)
+
+// taskMgr 是基于二叉堆、秒级精度的旧实现，仅作为时间轮的基准对照（见 wheel_bench_test.go）。
+type taskMgr struct {
+	id    int64
+	li    *pqueue.Queue[task, int64]
+	index map[int64]*pqueue.Handle[task, int64]
+	mu    sync.Mutex
+}
+
+func newTaskMgr() *taskMgr {
+	return &taskMgr{
+		li:    pqueue.NewMin[task, int64](),
+		index: make(map[int64]*pqueue.Handle[task, int64]),
+	}
+}
+
+func (mgr *taskMgr) genId() int64 {
+	mgr.id++
+	return mgr.id
+}
+
+func (mgr *taskMgr) AddTask(execTime int64, fn func()) int64 {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	id := mgr.genId()
+	mgr.index[id] = mgr.li.Push(task{
+		execTime: execTime,
+		id:       id,
+		fn:       fn,
+	}, execTime)
+	return id
+}
+
+func (mgr *taskMgr) RemoveTask(id int64) {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	if h, ok := mgr.index[id]; ok {
+		mgr.li.Remove(h)
+		delete(mgr.index, id)
+	}
+}
+
+func (mgr *taskMgr) GetRunableTasks(time int64) []task {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	var tasks []task
+	for {
+		t, execTime, ok := mgr.li.Peek()
+		if !ok || execTime > time {
+			break
+		}
+		mgr.li.Pop()
+		delete(mgr.index, t.id)
+		tasks = append(tasks, t)
+	}
+	return tasks
+}
+
+type task struct {
+	execTime int64
+	id       int64
+	fn       func()
+}
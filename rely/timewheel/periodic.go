@@ -0,0 +1,167 @@
+package timewheel
+
+import (
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidInterval = fmt.Errorf("interval must be positive")
+)
+
+// PeriodicMode 决定周期任务的下一次触发时间如何计算。
+type PeriodicMode int8
+
+const (
+	// FixedRate 按计划时间对齐触发：第 n 次在 first+n*interval，与执行耗时无关。
+	FixedRate PeriodicMode = iota
+	// FixedDelay 在上一次执行结束后再等待 interval 触发，不会并发执行。
+	FixedDelay
+)
+
+// MissedFire 决定时间轮被阻塞、错过若干次触发（晚于计划时间两个 tick 以上）后的处理方式。
+// 仅对 FixedRate 与 cron 任务有效。
+type MissedFire int8
+
+const (
+	// FireOnce 将所有错过的触发合并为一次执行。
+	FireOnce MissedFire = iota
+	// FireAll 补执行每一次错过的触发：同一批到期的多次执行在一个 goroutine 中依次进行，不会并发。
+	FireAll
+	// Skip 丢弃错过的触发，只执行仍然准时的那次。
+	Skip
+)
+
+type periodicConfig struct {
+	mode   PeriodicMode
+	missed MissedFire
+}
+
+type PeriodicOption func(*periodicConfig)
+
+// WithMode 设置周期任务的模式，默认 FixedRate。
+func WithMode(mode PeriodicMode) PeriodicOption {
+	return func(c *periodicConfig) {
+		c.mode = mode
+	}
+}
+
+// WithMissedFire 设置错过触发时的处理方式，默认 FireOnce。
+func WithMissedFire(policy MissedFire) PeriodicOption {
+	return func(c *periodicConfig) {
+		c.missed = policy
+	}
+}
+
+// schedule 计算周期任务的下一次触发时间。
+type schedule interface {
+	// next 返回严格晚于 t 的下一次触发时间；零值表示不再触发。
+	next(t time.Time) time.Time
+}
+
+// every 是固定间隔的 schedule。
+type every time.Duration
+
+func (e every) next(t time.Time) time.Time { return t.Add(time.Duration(e)) }
+
+// dueFires 统计计划在 when 的触发到 now 为止一共到期了几次，返回次数、最后一次到期时间与下一次触发时间。
+func dueFires(s schedule, when, now time.Time) (n int, last, next time.Time) {
+	if e, ok := s.(every); ok {
+		k := max(now.Sub(when)/time.Duration(e), 0)
+		last = when.Add(k * time.Duration(e))
+		return int(k) + 1, last, last.Add(time.Duration(e))
+	}
+	n, last, next = 1, when, s.next(when)
+	for !next.IsZero() && !next.After(now) {
+		n++
+		last, next = next, s.next(next)
+	}
+	return n, last, next
+}
+
+// AddPeriodicTask 每隔 interval 执行一次 fn（首次在 interval 之后），直到 Stop/RemoveTask。
+func (t *ticker) AddPeriodicTask(interval time.Duration, fn Task, opts ...PeriodicOption) (*Timer, error) {
+	if interval <= 0 {
+		return nil, ErrInvalidInterval
+	}
+	var pc periodicConfig
+	for _, opt := range opts {
+		opt(&pc)
+	}
+	return t.addPeriodic(every(interval), t.cfg.clock.Now().Add(interval), fn, pc)
+}
+
+// AddCronTask 按 cron 表达式执行 fn，表达式格式见 parseCron；默认使用本地时区。
+//
+// 默认 FixedRate，按 cron 计划时间触发；WithMode(FixedDelay) 时在上一次执行结束后取下一个 cron 时间触发，
+// 执行期间错过的计划时间被跳过，不会并发执行。
+func (t *ticker) AddCronTask(spec string, fn Task, opts ...PeriodicOption) (*Timer, error) {
+	sched, err := parseCron(spec, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	pc := periodicConfig{}
+	for _, opt := range opts {
+		opt(&pc)
+	}
+	first := sched.next(t.cfg.clock.Now())
+	if first.IsZero() {
+		return nil, fmt.Errorf("cron spec %q never fires", spec)
+	}
+	return t.addPeriodic(sched, first, fn, pc)
+}
+
+func (t *ticker) addPeriodic(sched schedule, first time.Time, fn Task, pc periodicConfig) (*Timer, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil, ErrTickerClosed
+	}
+	t.id++
+	tm := &Timer{tw: t, id: t.id, fn: fn, sched: sched, mode: pc.mode, missed: pc.missed}
+	t.scheduleLocked(tm, first)
+	return tm, nil
+}
+
+// firePeriodicLocked 处理一个到期的周期任务：按 MissedFire 决定执行次数并安排下一次触发。
+func (t *ticker) firePeriodicLocked(tm *Timer, now time.Time, jobs []func()) []func() {
+	if tm.mode == FixedDelay {
+		// 执行结束后再安排下一次；期间 Reset/Stop 会改变 seq，使本次不再重新安排。
+		seq := tm.seq
+		return append(jobs, t.jobLocked(tm, 1, func() {
+			if t.closed || tm.canceled || tm.seq != seq {
+				return
+			}
+			next := tm.sched.next(t.cfg.clock.Now())
+			if next.IsZero() {
+				tm.canceled = true
+				delete(t.timers, tm.id)
+				return
+			}
+			t.scheduleLocked(tm, next)
+		}))
+	}
+
+	n, last, next := dueFires(tm.sched, tm.when, now)
+	runs := n
+	switch tm.missed {
+	case FireOnce:
+		runs = 1
+	case Skip:
+		runs = 0
+		// 正常情况下任务最多晚一个 tick（加上 ticker 的抖动）被处理。
+		if now.Sub(last) < 2*t.cfg.tick {
+			runs = 1
+		}
+	}
+	if runs > 0 {
+		jobs = append(jobs, t.jobLocked(tm, runs, nil))
+	}
+	if next.IsZero() {
+		tm.canceled = true
+		delete(t.timers, tm.id)
+		return jobs
+	}
+	t.scheduleLocked(tm, next)
+	return jobs
+}
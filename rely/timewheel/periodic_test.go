@@ -0,0 +1,135 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestTicker 返回一个不启动后台 goroutine 的 ticker，由测试直接调用 advance 推进。
+func newTestTicker(t *testing.T) *ticker {
+	t.Helper()
+	tk := newTicker(config{clock: realClock{}, tick: 10 * time.Millisecond, bits: 6, levels: 4})
+	t.Cleanup(func() { _ = tk.Close() })
+	return tk
+}
+
+func run(jobs []func()) int {
+	for _, job := range jobs {
+		job()
+	}
+	return len(jobs)
+}
+
+func TestPeriodic_MissedFire(t *testing.T) {
+	cases := []struct {
+		policy MissedFire
+		want   int
+	}{
+		{FireOnce, 1},
+		{FireAll, 5},
+		{Skip, 0},
+	}
+	for _, c := range cases {
+		tk := newTestTicker(t)
+		count := 0
+		tm, _ := tk.AddPeriodicTask(100*time.Millisecond, Func(func() { count++ }), WithMissedFire(c.policy))
+		first := tm.When()
+
+		// 时间轮被阻塞了 4 个周期多一点：计划在 first..first+400ms 的 5 次触发同时到期，
+		// 补执行的几次在同一个回调中依次进行。
+		if n := run(tk.advance(first.Add(430 * time.Millisecond))); n != min(c.want, 1) || count != c.want {
+			t.Fatalf("policy %d: expected %d runs got %d in %d jobs", c.policy, c.want, count, n)
+		}
+		if want := first.Add(500 * time.Millisecond); !tm.When().Equal(want) {
+			t.Fatalf("policy %d: expected next fire at %v got %v", c.policy, want, tm.When())
+		}
+		// 此后准时的触发正常执行。
+		if n := run(tk.advance(first.Add(510 * time.Millisecond))); n != 1 {
+			t.Fatalf("policy %d: expected on-time run got %d", c.policy, n)
+		}
+	}
+}
+
+func TestPeriodic_FixedDelayAndStop(t *testing.T) {
+	tk := newTestTicker(t)
+	tm, _ := tk.AddPeriodicTask(50*time.Millisecond, Func(func() {}), WithMode(FixedDelay))
+	first := tm.When()
+
+	jobs := tk.advance(first.Add(500 * time.Millisecond))
+	if len(jobs) != 1 {
+		t.Fatalf("expected a single run got %d", len(jobs))
+	}
+	// 执行结束前不会再次触发。
+	if n := len(tk.advance(first.Add(time.Second))); n != 0 {
+		t.Fatalf("expected no run while executing got %d", n)
+	}
+	before := time.Now()
+	run(jobs)
+	if next := tm.When(); next.Before(before.Add(50 * time.Millisecond)) {
+		t.Fatalf("expected next fire 50ms after completion got %v", next.Sub(before))
+	}
+
+	if !tm.Stop() || tm.Stop() {
+		t.Fatalf("expected Stop to succeed exactly once")
+	}
+	if n := len(tk.advance(time.Now().Add(time.Minute))); n != 0 {
+		t.Fatalf("expected no run after Stop got %d", n)
+	}
+	// Reset 重新启动已停止的周期任务。
+	if tm.Reset(time.Millisecond) {
+		t.Fatalf("expected Reset of a stopped timer to report inactive")
+	}
+	if n := len(tk.advance(time.Now().Add(2 * time.Minute))); n != 1 {
+		t.Fatalf("expected run after Reset got %d", n)
+	}
+}
+
+func TestCron_FixedDelay(t *testing.T) {
+	tk := newTestTicker(t)
+	tm, err := tk.AddCronTask("* * * * * *", Func(func() {}), WithMode(FixedDelay))
+	if err != nil {
+		t.Fatalf("add cron: %v", err)
+	}
+	first := tm.When()
+	jobs := tk.advance(first.Add(5 * time.Second))
+	if len(jobs) != 1 {
+		t.Fatalf("expected a single run got %d", len(jobs))
+	}
+	if n := len(tk.advance(first.Add(10 * time.Second))); n != 0 {
+		t.Fatalf("expected no run while executing got %d", n)
+	}
+	// 执行结束后按 cron 取当前时间之后的下一个触发时间。
+	before := time.Now()
+	run(jobs)
+	if next := tm.When(); !next.After(before) || next.Nanosecond() != 0 {
+		t.Fatalf("expected next fire on the next second after completion got %v", next)
+	}
+}
+
+func TestCron_Task(t *testing.T) {
+	tk := newTestTicker(t)
+	count := 0
+	tm, err := tk.AddCronTask("* * * * * *", Func(func() { count++ }))
+	if err != nil {
+		t.Fatalf("add cron: %v", err)
+	}
+	first := tm.When()
+	if first.Nanosecond() != 0 || !first.After(time.Now().Add(-time.Second)) {
+		t.Fatalf("unexpected first fire %v", first)
+	}
+	run(tk.advance(first.Add(tk.cfg.tick)))
+	run(tk.advance(first.Add(time.Second + tk.cfg.tick)))
+	if count != 2 || !tm.When().Equal(first.Add(2*time.Second)) {
+		t.Fatalf("expected 2 runs got %d next=%v", count, tm.When())
+	}
+	tk.RemoveTask(tm.ID())
+	if run(tk.advance(first.Add(time.Minute))); count != 2 {
+		t.Fatalf("expected no run after RemoveTask got %d", count)
+	}
+	if _, err := tk.AddCronTask("0 0 30 2 *", Func(func() {})); err == nil {
+		t.Fatalf("expected error for a spec that never fires")
+	}
+	if _, err := tk.AddPeriodicTask(0, Func(func() {})); err != ErrInvalidInterval {
+		t.Fatalf("expected ErrInvalidInterval got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package timewheel
+
+import (
+	"context"
+	"fmt"
+)
+
+// Task 是时间轮执行的任务。ctx 在任务被 Stop/RemoveTask 或时间轮 Close 时取消；
+// 返回的错误（以及 panic）交给 WithOnError 设置的回调。
+type Task func(ctx context.Context) error
+
+// Func 将无参函数包装为 Task。
+func Func(fn func()) Task {
+	return func(context.Context) error {
+		fn()
+		return nil
+	}
+}
+
+// Pool 是执行到期任务的协程池。
+type Pool interface {
+	AddTask(task func())
+}
+
+// PoolFunc 将函数适配为 Pool，例如 PoolFunc(func(f func()) { p.AddTask(f) }) 可接入 *go_pool.Pool。
+type PoolFunc func(task func())
+
+func (f PoolFunc) AddTask(task func()) { f(task) }
+
+// WithPool 使用调用方提供的协程池执行到期任务；Close 不会关闭该协程池。
+func WithPool(pool Pool) Option {
+	return func(c *config) {
+		c.pool = pool
+	}
+}
+
+// WithInline 让到期任务在 tick 回调（时钟的 goroutine）中同步执行，任务应当足够短，否则会推迟后续 tick。
+func WithInline() Option {
+	return func(c *config) {
+		c.inline = true
+	}
+}
+
+// WithOnError 设置任务返回错误或 panic 时的回调，id 为任务 id；回调在执行任务的 goroutine 中调用。
+func WithOnError(fn func(id int64, err error)) Option {
+	return func(c *config) {
+		c.onError = fn
+	}
+}
+
+// runCtx 是一个任务正在执行的各次调用共享的 ctx，最后一次调用结束时释放。
+type runCtx struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	active int
+}
+
+// jobLocked 为任务创建一个依次执行 runs 次的回调（ctx 被取消后不再执行后续几次）；after 非空时在执行结束后持锁调用。
+func (t *ticker) jobLocked(tm *Timer, runs int, after func()) func() {
+	rc := tm.run
+	if rc == nil {
+		ctx, cancel := context.WithCancel(t.ctx)
+		rc = &runCtx{ctx: ctx, cancel: cancel}
+		tm.run = rc
+		t.running[tm.id] = tm
+	}
+	rc.active++
+	return func() {
+		for i := 0; i < runs && (i == 0 || rc.ctx.Err() == nil); i++ {
+			if err := t.call(tm, rc.ctx); err != nil && t.cfg.onError != nil {
+				t.cfg.onError(tm.id, err)
+			}
+		}
+		t.mu.Lock()
+		rc.active--
+		if rc.active == 0 {
+			rc.cancel()
+			if tm.run == rc {
+				tm.run = nil
+				delete(t.running, tm.id)
+			}
+		}
+		if after != nil {
+			after()
+		}
+		t.mu.Unlock()
+	}
+}
+
+func (t *ticker) call(tm *Timer, ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("timewheel: task %d panicked: %v", tm.id, r)
+		}
+	}()
+	return tm.fn(ctx)
+}
+
+// cancelRunLocked 取消任务正在执行的调用的 ctx。
+func (t *ticker) cancelRunLocked(tm *Timer) {
+	if tm.run != nil {
+		tm.run.cancel()
+		tm.run = nil
+		delete(t.running, tm.id)
+	}
+}
@@ -1,65 +1,152 @@
 package timewheel
 
 import (
+	"context"
+	"sync"
 	"time"
-
-	"github.com/arknights-w/go-utils/go_pool"
 )
 
 type ticker struct {
-	start  int64
-	now    int64
-	ticker *time.Ticker
+	cfg   config
+	start time.Time
+
+	mu     sync.Mutex
+	closed bool
+	id     int64
+	wheel  *wheel
+	timers map[int64]*Timer
+	// running 是正在执行（ctx 尚未释放）的任务，已不在时间轮中的任务也能被 RemoveTask 取消。
+	running map[int64]*Timer
+	due     []*Timer
 
-	pool *go_pool.Pool
-	mgr  *taskMgr
+	// ctx 是所有任务 ctx 的父 ctx，Close 时取消。
+	ctx       context.Context
+	cancelAll context.CancelFunc
 
-	cancel chan struct{}
+	stop func()
+	once sync.Once
 }
 
-func (t *ticker) demon(spanTime int64) {
-	t.start = time.Now().Unix()
-	t.now = t.start
-	t.ticker = time.NewTicker(time.Duration(spanTime) * time.Second)
-	for {
-		select {
-		case <-t.cancel:
-			t.ticker.Stop()
-			return
-		case <-t.ticker.C:
-			t.now += spanTime
-			tasks := t.mgr.GetRunableTasks(t.now)
-			for _, task := range tasks {
-				t.pool.AddTask(task.fn)
-			}
+// tickOf 返回时间 t 所在的 tick（向上取整，保证任务不会提前执行）。
+func (t *ticker) tickOf(at time.Time) int64 {
+	d := at.Sub(t.start)
+	if d <= 0 {
+		return 0
+	}
+	return int64((d + t.cfg.tick - 1) / t.cfg.tick)
+}
+
+func (t *ticker) onTick(now time.Time) {
+	for _, job := range t.advance(now) {
+		switch {
+		case t.cfg.inline:
+			job()
+		case t.cfg.pool != nil:
+			t.cfg.pool.AddTask(job)
+		default:
+			go job()
 		}
 	}
 }
 
-func (t *ticker) AddDelayedTask(delay int64, fn func()) (int64, error) {
-	select {
-	case <-t.cancel:
-		return 0, ErrTickerClosed
-	default:
-		return t.mgr.AddTask(t.now+delay, fn), nil
+// advance 处理到 now 为止的所有 tick（阻塞后一次性追赶），返回需要执行的回调。
+func (t *ticker) advance(now time.Time) []func() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.due = t.wheel.advance(int64(now.Sub(t.start)/t.cfg.tick), t.due[:0])
+	var jobs []func()
+	for _, tm := range t.due {
+		if tm.sched != nil {
+			jobs = t.firePeriodicLocked(tm, now, jobs)
+			continue
+		}
+		delete(t.timers, tm.id)
+		jobs = append(jobs, t.jobLocked(tm, 1, nil))
+	}
+	clear(t.due)
+	return jobs
+}
+
+func (t *ticker) add(execTime time.Time, fn Task) (*Timer, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil, ErrTickerClosed
+	}
+	t.id++
+	tm := &Timer{tw: t, id: t.id, fn: fn}
+	t.scheduleLocked(tm, execTime)
+	return tm, nil
+}
+
+// scheduleLocked 将（不在时间轮中的）任务安排在 at 执行。
+func (t *ticker) scheduleLocked(tm *Timer, at time.Time) {
+	tm.seq++
+	tm.when = at
+	tm.expire = t.tickOf(at)
+	t.wheel.add(tm)
+	t.timers[tm.id] = tm
+}
+
+// stopLocked 取消任务：周期任务在未被取消前总是返回 true（即使正在执行中），一次性任务同 removeLocked。
+func (t *ticker) stopLocked(tm *Timer) bool {
+	if tm.sched == nil {
+		return t.removeLocked(tm)
 	}
+	if tm.canceled {
+		return false
+	}
+	tm.canceled = true
+	tm.seq++
+	t.wheel.remove(tm)
+	delete(t.timers, tm.id)
+	return true
 }
 
-func (t *ticker) AddScheduledTask(execTime int64, fn func()) (int64, error) {
-	select {
-	case <-t.cancel:
-		return 0, ErrTickerClosed
-	default:
-		return t.mgr.AddTask(execTime, fn), nil
+// removeLocked 将任务从时间轮中移除；任务不在等待中时返回 false。
+func (t *ticker) removeLocked(tm *Timer) bool {
+	if !t.wheel.remove(tm) {
+		return false
 	}
+	delete(t.timers, tm.id)
+	return true
+}
+
+func (t *ticker) AddDelayedTask(delay time.Duration, fn Task) (*Timer, error) {
+	return t.add(t.cfg.clock.Now().Add(delay), fn)
+}
+
+func (t *ticker) AddScheduledTask(execTime time.Time, fn Task) (*Timer, error) {
+	return t.add(execTime, fn)
 }
 
 func (t *ticker) RemoveTask(id int64) {
-	t.mgr.RemoveTask(id)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tm, ok := t.timers[id]
+	if !ok {
+		tm, ok = t.running[id]
+	}
+	if ok {
+		t.stopLocked(tm)
+		t.cancelRunLocked(tm)
+	}
 }
 
 func (t *ticker) Close() error {
-	close(t.cancel)
-	t.pool.Close()
+	t.once.Do(func() {
+		t.mu.Lock()
+		t.closed = true
+		t.mu.Unlock()
+		t.cancelAll()
+		if t.stop != nil {
+			if t.cfg.inline {
+				// inline 模式下 Close 可能在 tick 回调中被调用，不能等待回调返回。
+				go t.stop()
+			} else {
+				t.stop()
+			}
+		}
+	})
 	return nil
 }
@@ -0,0 +1,60 @@
+package timewheel
+
+import "time"
+
+// Timer 是已添加任务的句柄，语义类似 time.Timer；Stop/Reset 可在任务回调中安全调用。
+//
+// Timer 同时是时间轮中的节点：以侵入式双向链表挂在某个槽上，取消与重新调度均为 O(1)。
+type Timer struct {
+	tw     *ticker
+	id     int64
+	when   time.Time
+	expire int64 // 到期的 tick 序号
+	fn     Task
+
+	// sched 非空表示周期任务；canceled 表示周期任务已被 Stop，seq 在每次（重新）安排时递增。
+	sched    schedule
+	mode     PeriodicMode
+	missed   MissedFire
+	canceled bool
+	seq      uint64
+	// run 是正在执行的调用共享的 ctx。
+	run *runCtx
+
+	prev, next *Timer
+	slot       *slot
+}
+
+// ID 返回任务 id，可用于 TimeWheel.RemoveTask。
+func (tm *Timer) ID() int64 { return tm.id }
+
+// When 返回任务计划执行的时间。
+func (tm *Timer) When() time.Time {
+	tm.tw.mu.Lock()
+	defer tm.tw.mu.Unlock()
+	return tm.when
+}
+
+// Stop 取消任务并取消其正在执行的 ctx；任务已执行（一次性任务）、已取消或时间轮已关闭时返回 false。
+func (tm *Timer) Stop() bool {
+	tm.tw.mu.Lock()
+	defer tm.tw.mu.Unlock()
+	tm.tw.cancelRunLocked(tm)
+	return !tm.tw.closed && tm.tw.stopLocked(tm)
+}
+
+// Reset 将任务改为在 d 之后执行，已执行或已取消的任务会被重新加入；返回调用前任务是否仍在等待。
+// 周期任务从该时间起继续按原周期触发。
+// 时间轮已关闭时不做任何事并返回 false。
+func (tm *Timer) Reset(d time.Duration) bool {
+	t := tm.tw
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return false
+	}
+	active := t.stopLocked(tm)
+	tm.canceled = false
+	t.scheduleLocked(tm, t.cfg.clock.Now().Add(d))
+	return active
+}
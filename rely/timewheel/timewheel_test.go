@@ -1,30 +1,227 @@
 package timewheel_test
 
 import (
+	"context"
+	"errors"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/arknights-w/go-utils/go_pool"
 	"github.com/arknights-w/go-utils/rely/timewheel"
 )
 
+func newFake(t *testing.T) (timewheel.TimeWheel, *timewheel.FakeClock) {
+	t.Helper()
+	clock := timewheel.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	tw := timewheel.NewTimeWheel(timewheel.WithClock(clock), timewheel.WithTick(5*time.Millisecond))
+	t.Cleanup(func() { _ = tw.Close() })
+	return tw, clock
+}
+
 func TestXxx(t *testing.T) {
-	tw := timewheel.NewTimeWheel()
-	defer tw.Close()
-	var ids [10]int64
+	tw, clock := newFake(t)
+	var ran []int
+	var timers [10]*timewheel.Timer
 	var err error
 	for i := range 10 {
-		ids[i], err = tw.AddDelayedTask(int64(i), func() {
-			println("Delayed task executed:", i)
-		})
+		timers[i], err = tw.AddDelayedTask(time.Duration(i)*time.Second, timewheel.Func(func() {
+			ran = append(ran, i)
+		}))
 		if err != nil {
 			t.Fatalf("Failed to add delayed task %d: %v", i, err)
 		}
 	}
-	for idx, id := range ids {
-		if idx%3 == 0 {
-			tw.RemoveTask(id)
-			println("Removed task with ID:", idx)
+	for idx, tm := range timers {
+		switch idx % 3 {
+		case 0:
+			tw.RemoveTask(tm.ID())
+		case 1:
+			// 推迟到测试结束之后
+			tm.Reset(time.Hour)
 		}
 	}
-	time.Sleep(time.Second * 9) // Wait for tasks to execute
+	clock.Advance(9 * time.Second) // FakeClock 同步执行到期任务，无需等待
+
+	if len(ran) != 3 || ran[0] != 2 || ran[1] != 5 || ran[2] != 8 {
+		t.Fatalf("expected tasks [2 5 8] executed in order got %v", ran)
+	}
+}
+
+func TestScheduledTask(t *testing.T) {
+	tw, clock := newFake(t)
+	start := clock.Now()
+	var at time.Time
+	if _, err := tw.AddScheduledTask(start.Add(30*time.Millisecond), timewheel.Func(func() { at = clock.Now() })); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	clock.Advance(29 * time.Millisecond)
+	if !at.IsZero() {
+		t.Fatalf("task executed early at %v", at.Sub(start))
+	}
+	clock.Advance(5 * time.Millisecond)
+	if at.Sub(start) != 30*time.Millisecond {
+		t.Fatalf("expected task executed at 30ms got %v", at.Sub(start))
+	}
+	_ = tw.Close()
+	if _, err := tw.AddDelayedTask(time.Millisecond, timewheel.Func(func() {})); err != timewheel.ErrTickerClosed {
+		t.Fatalf("expected ErrTickerClosed got %v", err)
+	}
+}
+
+func TestTimer_StopResetInCallback(t *testing.T) {
+	tw, clock := newFake(t)
+
+	var self, other *timewheel.Timer
+	var runs []int
+	other, _ = tw.AddDelayedTask(time.Hour, timewheel.Func(func() { runs = append(runs, -1) }))
+	self, _ = tw.AddDelayedTask(5*time.Millisecond, func(context.Context) error {
+		runs = append(runs, len(runs)+1)
+		if len(runs) == 1 {
+			// 在回调中取消其它任务并重新调度自己。
+			if !other.Stop() || other.Stop() {
+				t.Errorf("expected Stop to succeed exactly once")
+			}
+			if self.Reset(5 * time.Millisecond) {
+				t.Errorf("expected Reset of a fired timer to report inactive")
+			}
+		}
+		return nil
+	})
+	clock.Advance(time.Second)
+	if len(runs) != 2 || runs[0] != 1 || runs[1] != 2 {
+		t.Fatalf("expected two runs of self got %v", runs)
+	}
+	if self.Stop() {
+		t.Fatalf("expected Stop of a fired timer to return false")
+	}
+	self.Reset(time.Minute)
+	if want := clock.Now().Add(time.Minute); !self.When().Equal(want) {
+		t.Fatalf("expected When %v got %v", want, self.When())
+	}
+	_ = tw.Close()
+	if self.Stop() || self.Reset(time.Millisecond) {
+		t.Fatalf("expected Stop/Reset to fail after Close")
+	}
+}
+
+func TestPeriodicTask(t *testing.T) {
+	tw, clock := newFake(t)
+	count := 0
+	tm, _ := tw.AddPeriodicTask(time.Second, timewheel.Func(func() { count++ }))
+	clock.Advance(10 * time.Second)
+	if count != 10 {
+		t.Fatalf("expected 10 runs got %d", count)
+	}
+	tm.Stop()
+	clock.Advance(10 * time.Second)
+	if count != 10 {
+		t.Fatalf("expected no run after Stop got %d", count)
+	}
+}
+
+func TestRealClock(t *testing.T) {
+	tw := timewheel.NewTimeWheel(timewheel.WithTick(time.Millisecond))
+	defer tw.Close()
+	done := make(chan struct{})
+	_, _ = tw.AddDelayedTask(5*time.Millisecond, timewheel.Func(func() { close(done) }))
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("task not executed")
+	}
+}
+
+func TestTask_ContextAndErrors(t *testing.T) {
+	clock := timewheel.NewFakeClock(time.Unix(0, 0))
+	var errs []error
+	tw := timewheel.NewTimeWheel(timewheel.WithClock(clock), timewheel.WithOnError(func(id int64, err error) {
+		errs = append(errs, err)
+	}))
+	defer tw.Close()
+
+	boom := errors.New("boom")
+	_, _ = tw.AddDelayedTask(time.Second, func(context.Context) error { return boom })
+	_, _ = tw.AddDelayedTask(time.Second, func(context.Context) error { panic("oops") })
+	clock.Advance(time.Second)
+	if len(errs) != 2 || !errors.Is(errs[0], boom) || errs[1] == nil {
+		t.Fatalf("expected error and panic reported got %v", errs)
+	}
+
+	// Stop 在任务执行中取消其 ctx。
+	var tm *timewheel.Timer
+	var stopped error
+	tm, _ = tw.AddDelayedTask(time.Second, func(ctx context.Context) error {
+		tm.Stop()
+		stopped = ctx.Err()
+		return nil
+	})
+	clock.Advance(time.Second)
+	if !errors.Is(stopped, context.Canceled) {
+		t.Fatalf("expected ctx canceled by Stop got %v", stopped)
+	}
+
+	// RemoveTask 同样能取消正在执行的一次性任务。
+	var removed error
+	tm, _ = tw.AddDelayedTask(time.Second, func(ctx context.Context) error {
+		tw.RemoveTask(tm.ID())
+		removed = ctx.Err()
+		return nil
+	})
+	clock.Advance(time.Second)
+	if !errors.Is(removed, context.Canceled) {
+		t.Fatalf("expected ctx canceled by RemoveTask got %v", removed)
+	}
+}
+
+// pool 记录提交的任务，由测试手动执行。
+type pool struct {
+	mu   sync.Mutex
+	jobs []func()
+}
+
+func (p *pool) AddTask(task func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.jobs = append(p.jobs, task)
+}
+
+func TestTask_PoolAndClose(t *testing.T) {
+	clock := timewheel.NewFakeClock(time.Unix(0, 0))
+	p := &pool{}
+	tw := timewheel.NewTimeWheel(timewheel.WithClock(clock), timewheel.WithPool(p))
+
+	var ctxErr error
+	_, _ = tw.AddDelayedTask(time.Second, func(ctx context.Context) error {
+		ctxErr = ctx.Err()
+		return nil
+	})
+	clock.Advance(time.Second)
+	if len(p.jobs) != 1 || ctxErr != nil {
+		t.Fatalf("expected task handed to the pool got %d jobs", len(p.jobs))
+	}
+	// Close 取消尚未执行完的任务的 ctx。
+	_ = tw.Close()
+	p.jobs[0]()
+	if !errors.Is(ctxErr, context.Canceled) {
+		t.Fatalf("expected ctx canceled by Close got %v", ctxErr)
+	}
+}
+
+func TestTask_PoolFuncWithGoPool(t *testing.T) {
+	clock := timewheel.NewFakeClock(time.Unix(0, 0))
+	gp := go_pool.NewPool(1, 1)
+	defer gp.Close()
+	tw := timewheel.NewTimeWheel(timewheel.WithClock(clock),
+		timewheel.WithPool(timewheel.PoolFunc(func(f func()) { gp.AddTask(f) })))
+	defer tw.Close()
+
+	done := make(chan struct{})
+	_, _ = tw.AddDelayedTask(time.Second, timewheel.Func(func() { close(done) }))
+	clock.Advance(time.Second)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("task not run by go_pool")
+	}
 }
@@ -0,0 +1,125 @@
+package timewheel
+
+// slot 是一个带哨兵的环形双向链表。
+type slot struct {
+	root Timer
+}
+
+func (s *slot) push(t *Timer) {
+	if s.root.next == nil {
+		s.root.next, s.root.prev = &s.root, &s.root
+	}
+	t.prev, t.next = s.root.prev, &s.root
+	s.root.prev.next = t
+	s.root.prev = t
+	t.slot = s
+}
+
+func (s *slot) remove(t *Timer) {
+	t.prev.next = t.next
+	t.next.prev = t.prev
+	t.prev, t.next, t.slot = nil, nil, nil
+}
+
+// takeAll 摘下槽上的全部节点，按插入顺序追加到 out。
+func (s *slot) takeAll(out []*Timer) []*Timer {
+	if s.root.next == nil {
+		return out
+	}
+	for t := s.root.next; t != &s.root; {
+		next := t.next
+		t.prev, t.next, t.slot = nil, nil, nil
+		out = append(out, t)
+		t = next
+	}
+	s.root.next, s.root.prev = &s.root, &s.root
+	return out
+}
+
+// wheel 是分层哈希时间轮（Varghese & Lauck，与 Linux/Kafka 的实现同构）：
+//   - 共 len(levels) 层，每层 1<<bits 个槽；第 l 层的一个槽跨越 1<<(bits*l) 个 tick
+//   - 到期 tick 距当前 tick 越远的任务放在越高的层；低层转完一圈时，高层对应槽中的任务下沉（cascade）
+//   - 超出最高层范围的任务先放在最高层最远的槽，下沉时重新计算位置
+//
+// add/remove 为 O(1)，advance 每个 tick 摊还 O(1)。非线程安全。
+type wheel struct {
+	bits   uint
+	mask   int64
+	max    int64 // 可直接定位的最大 tick 距离
+	levels [][]slot
+	cur    int64 // 下一个要处理的 tick
+	count  int
+}
+
+func newWheel(bits uint, levels int, cur int64) *wheel {
+	w := &wheel{
+		bits:   bits,
+		mask:   1<<bits - 1,
+		max:    1<<(bits*uint(levels)) - 1,
+		levels: make([][]slot, levels),
+		cur:    cur,
+	}
+	for i := range w.levels {
+		w.levels[i] = make([]slot, 1<<bits)
+	}
+	return w
+}
+
+// add 按 t.expire 放入对应的槽；已到期的任务放入下一个要处理的槽。
+func (w *wheel) add(t *Timer) {
+	w.place(t)
+	w.count++
+}
+
+func (w *wheel) place(t *Timer) {
+	exp, idx := t.expire, t.expire-w.cur
+	if idx < 0 {
+		exp, idx = w.cur, 0
+	} else if idx > w.max {
+		exp, idx = w.cur+w.max, w.max
+	}
+	level := 0
+	for idx>>(w.bits*uint(level+1)) > 0 {
+		level++
+	}
+	w.levels[level][(exp>>(w.bits*uint(level)))&w.mask].push(t)
+}
+
+// remove 将任务从时间轮中摘除；任务不在时间轮中时返回 false。
+func (w *wheel) remove(t *Timer) bool {
+	if t.slot == nil {
+		return false
+	}
+	t.slot.remove(t)
+	w.count--
+	return true
+}
+
+// advance 依次处理 tick w.cur..to，将到期任务按到期顺序追加到 due。
+func (w *wheel) advance(to int64, due []*Timer) []*Timer {
+	var moved []*Timer
+	for w.cur <= to {
+		if w.count == 0 {
+			w.cur = to + 1
+			break
+		}
+		index := w.cur & w.mask
+		if index == 0 {
+			for level := 1; level < len(w.levels); level++ {
+				i := (w.cur >> (w.bits * uint(level))) & w.mask
+				moved = w.levels[level][i].takeAll(moved[:0])
+				for _, t := range moved {
+					w.place(t)
+				}
+				if i != 0 {
+					break
+				}
+			}
+		}
+		n := len(due)
+		due = w.levels[0][index].takeAll(due)
+		w.count -= len(due) - n
+		w.cur++
+	}
+	return due
+}
@@ -0,0 +1,76 @@
+package timewheel
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+)
+
+// 时间轮与旧的堆实现（taskMgr）的对比：均为不含锁竞争与协程池的纯数据结构开销。
+
+var benchSizes = []int{1_000, 100_000}
+
+func BenchmarkWheel_AddRemove(b *testing.B) {
+	for _, n := range benchSizes {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			w := newWheel(6, 4, 0)
+			rnd := rand.New(rand.NewPCG(1, 2))
+			for i := range n {
+				w.add(&Timer{id: int64(i), expire: rnd.Int64N(1 << 20)})
+			}
+			tm := &Timer{}
+			b.ResetTimer()
+			for i := range b.N {
+				tm.expire = int64(i) & (1<<20 - 1)
+				w.add(tm)
+				w.remove(tm)
+			}
+		})
+	}
+}
+
+func BenchmarkTaskMgr_AddRemove(b *testing.B) {
+	for _, n := range benchSizes {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			mgr := newTaskMgr()
+			rnd := rand.New(rand.NewPCG(1, 2))
+			for range n {
+				mgr.AddTask(rnd.Int64N(1<<20), nil)
+			}
+			b.ResetTimer()
+			for i := range b.N {
+				mgr.RemoveTask(mgr.AddTask(int64(i)&(1<<20-1), nil))
+			}
+		})
+	}
+}
+
+// BenchmarkWheel_Expire 每次迭代添加一个任务并推进一个 tick，稳态下约 n 个任务在轮中。
+func BenchmarkWheel_Expire(b *testing.B) {
+	for _, n := range benchSizes {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			w := newWheel(6, 4, 0)
+			rnd := rand.New(rand.NewPCG(1, 2))
+			var due []*Timer
+			b.ResetTimer()
+			for i := range int64(b.N) {
+				w.add(&Timer{id: i, expire: i + 1 + rnd.Int64N(int64(2*n))})
+				due = w.advance(i, due[:0])
+			}
+		})
+	}
+}
+
+func BenchmarkTaskMgr_Expire(b *testing.B) {
+	for _, n := range benchSizes {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			mgr := newTaskMgr()
+			rnd := rand.New(rand.NewPCG(1, 2))
+			b.ResetTimer()
+			for i := range int64(b.N) {
+				mgr.AddTask(i+1+rnd.Int64N(int64(2*n)), nil)
+				_ = mgr.GetRunableTasks(i)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package timewheel
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// TestWheel_Cascade 在小时间轮（4 槽 × 3 层）上随机添加/删除任务，检查每个任务恰好在到期 tick 取出。
+func TestWheel_Cascade(t *testing.T) {
+	w := newWheel(2, 3, 0)
+	rnd := rand.New(rand.NewPCG(1, 2))
+	want := make(map[int64][]int64) // tick -> ids
+	var removed []*Timer
+	for id := range int64(2000) {
+		// 包含超出 4^3 个 tick 范围的任务。
+		tm := &Timer{id: id, expire: rnd.Int64N(300)}
+		w.add(tm)
+		if id%7 == 0 {
+			removed = append(removed, tm)
+			continue
+		}
+		want[tm.expire] = append(want[tm.expire], id)
+	}
+	for _, tm := range removed {
+		if !w.remove(tm) || w.remove(tm) {
+			t.Fatalf("remove %d should succeed exactly once", tm.id)
+		}
+	}
+
+	var due []*Timer
+	for tick := range int64(300) {
+		due = w.advance(tick, due[:0])
+		var got []int64
+		for _, tm := range due {
+			got = append(got, tm.id)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, want[tick]) {
+			t.Fatalf("tick %d: expected %v got %v", tick, want[tick], got)
+		}
+	}
+	if w.count != 0 {
+		t.Fatalf("expected empty wheel got %d", w.count)
+	}
+
+	// 已过期的任务在下一个 tick 取出；空轮直接跳到目标 tick。
+	w.add(&Timer{id: 1, expire: 10})
+	if due = w.advance(1000, due[:0]); len(due) != 1 || w.cur != 1001 {
+		t.Fatalf("expected overdue task at next tick got %d cur=%d", len(due), w.cur)
+	}
+}